@@ -1,9 +1,20 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github-project-template/internal/consts"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 )
 
+// configFile holds the path of the configuration file passed with --config.
+var configFile string
+
 // RootCmd represents the root command of the CLI tool.
 // It defines the command name, description, and usage for the tool that downloads GitHub repository contents for new projects.
 var RootCmd = &cobra.Command{
@@ -11,6 +22,81 @@ var RootCmd = &cobra.Command{
 	Short: "A CLI tool to download GitHub repository contents when creating a new project.",
 }
 
+// init registers the persistent --config flag and loads the configuration before any command runs.
+// Parameters: None.
+func init() {
+	cobra.OnInitialize(initConfig)
+	RootCmd.PersistentFlags().StringVar(&configFile, "config", consts.EMPTY_STRING, "Config file (default $HOME/.repo-stub.yaml)")
+}
+
+// initConfig reads the configuration file and REPO_STUB_* environment variables into Viper.
+// Keys match the long flag names, e.g. api-url or github-token, and environment variables use upper case with underscores, e.g. REPO_STUB_API_URL.
+// A missing default configuration file is not an error.
+// Parameters: None.
+func initConfig() {
+	if configFile != consts.EMPTY_STRING {
+		viper.SetConfigFile(configFile)
+	} else {
+		if home, err := os.UserHomeDir(); err == nil {
+			viper.AddConfigPath(home)
+		}
+		viper.SetConfigName(".repo-stub")
+		viper.SetConfigType("yaml")
+	}
+
+	viper.SetEnvPrefix("REPO_STUB")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok || configFile != consts.EMPTY_STRING {
+			fmt.Println(err)
+		}
+	}
+}
+
+// applyConfig copies values from the configuration file and environment onto every flag of cmd that was not set on the command line,
+// so command line flags always take precedence. Copied values do not mark their flag as changed, so Changed keeps meaning "set on the command line".
+// Parameters:
+// - cmd: A pointer to the Cobra command whose flags are updated.
+// Returns: The names of the flags set from configuration, mapped to where their value came from (VAR_SOURCE_ENV or VAR_SOURCE_CONFIG),
+// and an error if a configured value is not valid for its flag.
+func applyConfig(cmd *cobra.Command) (map[string]string, error) {
+	var err error
+	configured := map[string]string{}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || !viper.IsSet(f.Name) {
+			return
+		}
+
+		values := []string{viper.GetString(f.Name)}
+		if _, ok := f.Value.(pflag.SliceValue); ok {
+			values = viper.GetStringSlice(f.Name)
+		}
+
+		for _, value := range values {
+			if setErr := f.Value.Set(value); setErr != nil {
+				err = fmt.Errorf("invalid config value for %s: %v", f.Name, setErr)
+				return
+			}
+		}
+
+		configured[f.Name] = consts.VAR_SOURCE_CONFIG
+		if _, ok := os.LookupEnv(envName(f.Name)); ok {
+			configured[f.Name] = consts.VAR_SOURCE_ENV
+		}
+	})
+	return configured, err
+}
+
+// envName returns the environment variable read for a flag, e.g. REPO_STUB_API_URL for api-url.
+// Parameters:
+// - flag: The long name of the flag.
+// Returns: The name of the environment variable.
+func envName(flag string) string {
+	return "REPO_STUB_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
 // Execute runs the root command and handles any errors that occur during execution.
 // Returns: An error if the command execution fails.
 func Execute() error {
@@ -3,11 +3,23 @@ package cli
 import (
 	"fmt"
 	"github-project-template/internal/consts"
+	"github-project-template/internal/gitrepo"
+	"github-project-template/internal/hooks"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/prompt"
+	"github-project-template/internal/render"
+	"github-project-template/internal/sources"
+	"github-project-template/internal/trust"
 	"github-project-template/internal/types"
 	"github-project-template/internal/utils/repository"
+	"github-project-template/internal/vars"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/gookit/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -17,69 +29,317 @@ var (
 	options = types.CliFlags{}
 	// stubCmd represents a subcommand for the CLI tool.
 	stubCmd = &cobra.Command{}
-	// baseUrl is a string variable used to store the base URL for GitHub repository access.
-	baseUrl string
 )
 
 // init initializes the `stubCmd` subcommand for the CLI tool.
 // It sets up the command's usage, description, arguments, and execution function.
 // It also binds the command-line flags to Viper for configuration management and adds the subcommand to the root command.
-// Additionally, it constructs the base URL for GitHub repository access using the options provided.
 // Parameters: None.
 func init() {
 	stubCmd = &cobra.Command{
-		Use:   "stub <output-directory> [flags]",
+		Use:   "stub <output-directory> [template] [flags]",
 		Short: "Stub project",
-		Long:  "Stub project with templated files based on options",
-		Args:  cobra.ExactArgs(1),
+		Long:  "Stub project with templated files based on options.\nThe optional template argument is the same as --template, e.g. acme/templates@v2//go-cli or https://github.com/acme/templates/tree/main/go-cli",
+		Args:  cobra.RangeArgs(1, 2),
 		RunE:  run,
 	}
 
 	initFlags(stubCmd)
 	viper.BindPFlags(stubCmd.Flags())
 	RootCmd.AddCommand(stubCmd)
-
-	baseUrl = fmt.Sprintf("https://api.github.com/repos/%s/%s/contents", options.RepoOwner, options.RepoName)
 }
 
 // initFlags sets up the flags for the given Cobra command, defining various options for repository access and project configuration.
-// It adds flags for repository name, owner, branch name, GitHub token, project language, license type, and other options related to file inclusion and overwriting.
+// It adds flags for repository name, owner, branch name, GitHub token, project language, license type, template source, and other options related to file inclusion and overwriting.
 // Parameters:
 // - cmd: A pointer to the Cobra command for which the flags are being defined.
 func initFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&options.RepoName, "repo-name", "r", "vscode", "Name of the repository")
 	cmd.Flags().StringVarP(&options.RepoOwner, "repo-owner", "o", "ondrovic", "Owner of the repository")
-	cmd.Flags().StringVarP(&options.BranchName, "branch-name", "b", "master", "Branch name you wish to pull from")
-	cmd.Flags().StringVarP(&options.GithubToken, "github-token", "t", consts.EMPTY_STRING, "Github API token")
+	cmd.Flags().StringVarP(&options.BranchName, "branch-name", "b", consts.EMPTY_STRING, "Branch, tag, commit or semver range (e.g. ^2.1) you wish to pull from (default the repository's default branch)")
+	cmd.Flags().StringVarP(&options.GithubToken, "github-token", "t", consts.EMPTY_STRING, "API token for the template provider (alias --token)")
 	cmd.Flags().StringVarP(&options.ProjectLanguage, "project-language", "p", "go", "What language is your app in")
 	cmd.Flags().StringVarP(&options.LicenseType, "license-type", "l", "mit", "What license are you using")
-	cmd.Flags().BoolVarP(&options.IncludeMakefile, "include-makefile", "m", false, "Include a Makefile")
-	cmd.Flags().BoolVarP(&options.IncludeVersionFile, "include-version-file", "v", false, "Include a version file")
+	cmd.Flags().BoolVarP(&options.IncludeMakefile, "include-makefile", "m", false, "Include a Makefile (same as --include makefile)")
+	cmd.Flags().BoolVarP(&options.IncludeVersionFile, "include-version-file", "v", false, "Include a version file (same as --include version)")
+	cmd.Flags().StringArrayVar(&options.Includes, "include", nil, "Optional template category to include, as declared by the template manifest; repeat to include several")
 	cmd.Flags().BoolVarP(&options.OverwriteFiles, "overwrite-files", "w", false, "Overwrite existing files")
+	cmd.Flags().StringVar(&options.Template, "template", consts.EMPTY_STRING, "Template to stub from as owner/name[@ref][//subdir] or a pasted repository URL, overriding the repository flags; also accepts a local directory or archive")
+	cmd.Flags().StringArrayVar(&options.VarAssignments, "var", nil, "Template variable as key=value; repeat to set several")
+	cmd.Flags().StringVar(&options.VarsFile, "vars-file", consts.EMPTY_STRING, "YAML or JSON file of template variables")
+	cmd.Flags().BoolVar(&options.NoInput, "no-input", false, "Never prompt for template variables; fail when any are missing")
+	cmd.Flags().BoolVar(&options.NoHooks, "no-hooks", false, "Never run the hooks declared by the template")
+	cmd.Flags().StringVar(&options.AnswersFile, "save-answers", consts.EMPTY_STRING, "Save the values of the variables declared by the template to a YAML file, for replay with --vars-file")
+	cmd.Flags().BoolVar(&options.GitInit, "git-init", false, "Initialize a git repository in the output directory with an initial commit of exactly the written files")
+	cmd.Flags().StringVar(&options.InitialBranch, "initial-branch", "main", "Name of the initial branch with --git-init")
+	cmd.Flags().StringVar(&options.CommitMessage, "commit-message", "Initial commit", "Message of the initial commit with --git-init")
+	cmd.Flags().StringVar(&options.GitAuthorName, "git-author-name", consts.EMPTY_STRING, "Author name of the initial commit (default user.name of your git config)")
+	cmd.Flags().StringVar(&options.GitAuthorEmail, "git-author-email", consts.EMPTY_STRING, "Author email of the initial commit (default user.email of your git config)")
+	cmd.Flags().BoolVar(&options.GitNested, "git-nested", false, "Allow --git-init when the output directory is inside another git repository")
+	cmd.Flags().StringArrayVarP(&options.Sources, "source", "s", nil, "Template source to use instead of the GitHub repository: a local directory, a .zip/.tar.gz archive path or URL, or owner/name; repeat to layer sources, later ones overriding earlier ones")
+	cmd.Flags().StringVarP(&options.FetchMode, "fetch-mode", "f", consts.FETCH_CONTENTS, fmt.Sprintf("How to fetch the template from GitHub (%s, %s, %s)", consts.FETCH_CONTENTS, consts.FETCH_TARBALL, consts.FETCH_TREE))
+	cmd.Flags().StringVar(&options.Provider, "provider", consts.PROVIDER_GITHUB, fmt.Sprintf("Where the template repository is hosted (%s, %s, %s, %s)", consts.PROVIDER_GITHUB, consts.PROVIDER_GITLAB, consts.PROVIDER_GITEA, consts.PROVIDER_FORGEJO))
+	cmd.Flags().StringVar(&options.ApiUrl, "api-url", consts.EMPTY_STRING, fmt.Sprintf("Base URL of the provider API, e.g. a GitHub Enterprise Server (default %s), GitLab (default %s), Gitea or Forgejo instance", consts.GITHUB_API_URL, consts.GITLAB_API_URL))
+	cmd.Flags().SetNormalizeFunc(normalizeFlags)
+}
+
+// normalizeFlags maps flag aliases onto their canonical names.
+// Parameters:
+// - f: The flag set being parsed.
+// - name: The flag name as given on the command line.
+// Returns: The canonical flag name.
+func normalizeFlags(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	switch name {
+	case "token":
+		name = "github-token"
+	}
+	return pflag.NormalizedName(name)
 }
 
 // run is the execution function for the `stubCmd` subcommand.
-// It sets the output directory from the command arguments, updates the base URL if a branch name other than "master" is specified,
-// creates the output directory if it doesn't exist, and processes the repository based on the specified options.
+// It applies configuration file and environment values to unset flags, sets the output directory and template from the command arguments, maps the include flags onto optional categories, loads the template variables, creates the template source described by the options,
+// loads the template manifest and resolves the variables it declares (prompting for missing ones on a terminal), validates them against the manifest before the full template is fetched, prepares the hooks of the manifest and confirms them unless the template is trusted, creates the output directory if it doesn't exist,
+// and processes the template based on the specified options between running the pre and post hooks, aborting when a hook fails.
+// With --git-init, the output directory is checked first and finally turned into a git repository with an initial commit of the written files.
 // Parameters:
 // - cmd: A pointer to the Cobra command being executed.
 // - args: A slice of arguments provided to the command.
 // Returns: An error if any issues occur during execution.
 func run(cmd *cobra.Command, args []string) error {
 
+	configured, err := applyConfig(cmd)
+	if err != nil {
+		return err
+	}
+
 	options.OutputDirectory = args[0]
+	if len(args) > 1 {
+		if cmd.Flags().Changed("template") {
+			return fmt.Errorf("the template can be given either as an argument or with --template, not both")
+		}
+		options.Template = args[1]
+	}
+
+	if options.IncludeMakefile {
+		options.Includes = append(options.Includes, consts.CATEGORY_MAKEFILE)
+	}
+	if options.IncludeVersionFile {
+		options.Includes = append(options.Includes, consts.CATEGORY_VERSION)
+	}
 
-	if options.BranchName != "master" {
-		baseUrl = fmt.Sprintf("%s?ref=%s", baseUrl, options.BranchName)
+	var author gitrepo.Author
+	if options.GitInit {
+		if author, err = checkGitInit(); err != nil {
+			return err
+		}
+	}
+
+	if options.Vars, err = loadVars(cmd, configured); err != nil {
+		return err
+	}
+
+	src, err := sources.New(options)
+	if err != nil {
+		return err
+	}
+
+	m, err := manifest.Load(src)
+	if err != nil {
+		return err
+	}
+	if err := m.ResolveChoices(src); err != nil {
+		return err
+	}
+
+	var prompter prompt.Prompter
+	if !options.NoInput && prompt.IsInteractive() {
+		prompter = prompt.Terminal{}
+	}
+	if options.Vars, err = vars.Resolve(m.Variables, options.Vars, render.NewData(options), prompter); err != nil {
+		return err
+	}
+	if options.Vars, err = vars.Validate(m.Variables, options.Vars); err != nil {
+		return err
+	}
+	if options.AnswersFile != consts.EMPTY_STRING {
+		if err := vars.SaveAnswers(options.AnswersFile, m.Variables, options.Vars); err != nil {
+			return err
+		}
+	}
+
+	if err := sources.Fetch(src); err != nil {
+		return err
+	}
+
+	data := render.NewData(options)
+	pre, err := hooks.Prepare(consts.HOOK_PRE, m.Hooks.Pre, options.OutputDirectory, data)
+	if err != nil {
+		return err
+	}
+	post, err := hooks.Prepare(consts.HOOK_POST, m.Hooks.Post, options.OutputDirectory, data)
+	if err != nil {
+		return err
+	}
+	if options.NoHooks {
+		pre, post = nil, nil
+	}
+	if err := confirmHooks(src, append(pre, post...), prompter); err != nil {
+		return err
 	}
 
 	if err := os.MkdirAll(options.OutputDirectory, 0755); err != nil {
 		fmt.Println(err)
 	}
 
-	if err := repository.ProcessRepository(baseUrl, consts.EMPTY_STRING, options); err != nil {
+	if err := hooks.Run(pre, os.Stdout, os.Stderr); err != nil {
+		return err
+	}
+
+	written, err := repository.ProcessRepository(src, m, options)
+	if err != nil {
 		fmt.Println(err)
+		return nil
+	}
+
+	if err := hooks.Run(post, os.Stdout, os.Stderr); err != nil {
+		return err
+	}
+
+	if options.GitInit {
+		hash, err := gitrepo.Init(options.OutputDirectory, written, gitrepo.Options{Branch: options.InitialBranch, Message: options.CommitMessage, Author: author})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created the initial commit %s on %s\n", hash, color.New(color.FgCyan).Sprint(options.InitialBranch))
+	}
+	return nil
+}
+
+// checkGitInit makes sure --git-init can succeed before anything is fetched or written: the output directory must not be a git repository,
+// nor be inside one unless --git-nested is given, and the author of the initial commit must be known.
+// Returns: The author of the initial commit and an error if a repository cannot be initialized.
+func checkGitInit() (gitrepo.Author, error) {
+	root, err := gitrepo.Containing(options.OutputDirectory)
+	if err != nil {
+		return gitrepo.Author{}, err
+	}
+	if root != consts.EMPTY_STRING {
+		abs, _ := filepath.Abs(options.OutputDirectory)
+		if root == abs {
+			return gitrepo.Author{}, fmt.Errorf("%s is already a git repository", options.OutputDirectory)
+		}
+		if !options.GitNested {
+			return gitrepo.Author{}, fmt.Errorf("%s is inside the git repository %s; pass --git-nested to create a nested repository anyway", options.OutputDirectory, root)
+		}
+	}
+
+	return gitrepo.ResolveAuthor(options.GitAuthorName, options.GitAuthorEmail)
+}
+
+// confirmHooks makes sure the hooks of the template may run. Hooks of templates whose every origin is in the trust store run right away;
+// otherwise the exact commands are shown and the user has to confirm them, which fails when nobody can be prompted.
+// Every layer has to be trusted, since hooks may run files of any layer, so the hint names each untrusted one.
+// Parameters:
+// - src: The template source the hooks were declared by.
+// - commands: The hooks that are about to run.
+// - prompter: The Prompter used to ask for confirmation, or nil when not running interactively.
+// Returns: An error if the trust store could not be read or the hooks were not confirmed.
+func confirmHooks(src sources.TemplateSource, commands []hooks.Command, prompter prompt.Prompter) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	path, err := trustFile()
+	if err != nil {
+		return err
+	}
+	store, err := trust.Load(path)
+	if err != nil {
+		return err
+	}
+
+	origins := sources.Origins(src)
+	if store.Trusts(origins) {
+		return nil
+	}
+
+	fmt.Println(color.New(color.FgYellow).Sprint("The template wants to run these commands:"))
+	for _, command := range commands {
+		fmt.Printf("  %s\n", command)
 	}
 
+	hint := "trust the template with `repo-stub trust add`"
+	if untrusted := store.Untrusted(origins); len(untrusted) > 0 {
+		commands := make([]string, 0, len(untrusted))
+		for _, origin := range untrusted {
+			commands = append(commands, fmt.Sprintf("`repo-stub trust add %s %s`", origin.Source, origin.SHA))
+		}
+		hint = fmt.Sprintf("trust the template with %s", strings.Join(commands, " and "))
+	}
+	if prompter == nil {
+		return fmt.Errorf("the template is not trusted to run hooks; review them and %s, or skip them with --no-hooks", hint)
+	}
+
+	confirmed, err := prompter.Confirm("Run these commands?", false)
+	if err != nil {
+		return fmt.Errorf("failed to confirm hooks: %v", err)
+	}
+	if !confirmed {
+		return fmt.Errorf("hooks were not confirmed; skip them with --no-hooks or %s", hint)
+	}
 	return nil
 }
+
+// loadVars merges the template variables from every source, from lowest to highest precedence:
+// the owner of the template reference, the vars map of the configuration file, the --vars-file file, REPO_STUB_VAR_* environment variables and --var flags.
+// Explicitly set or configured --project-language, --license-type and --repo-owner flags count as the language, license and owner variables, so templates do not prompt for them;
+// their source is the flag, the environment or the configuration file they were set in.
+// Parameters:
+// - cmd: A pointer to the Cobra command being executed.
+// - configured: The flags set from configuration by applyConfig, mapped to their source.
+// Returns: The merged variables and an error if a variables file or assignment is invalid.
+func loadVars(cmd *cobra.Command, configured map[string]string) (map[string]types.Variable, error) {
+	flagVars, err := vars.Parse(options.VarAssignments)
+	if err != nil {
+		return nil, err
+	}
+
+	fileVars := map[string]types.Variable{}
+	if options.VarsFile != consts.EMPTY_STRING {
+		if fileVars, err = vars.LoadFile(options.VarsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	builtinVars := map[string]types.Variable{}
+	for flag, variable := range map[string]struct {
+		name  string
+		value string
+	}{
+		"project-language": {render.LANGUAGE, options.ProjectLanguage},
+		"license-type":     {render.LICENSE, options.LicenseType},
+		"repo-owner":       {render.OWNER, options.RepoOwner},
+	} {
+		source, ok := configured[flag]
+		if cmd.Flags().Changed(flag) {
+			source, ok = consts.VAR_SOURCE_FLAG, true
+		}
+		if ok {
+			builtinVars[variable.name] = types.Variable{Value: variable.value, Source: source}
+		}
+	}
+
+	referenceVars := map[string]types.Variable{}
+	if owner := sources.ReferenceOwner(options); owner != consts.EMPTY_STRING {
+		referenceVars[render.OWNER] = types.Variable{Value: owner, Source: consts.VAR_SOURCE_TEMPLATE}
+	}
+
+	return vars.Merge(
+		referenceVars,
+		vars.FromMap(viper.GetStringMap("vars"), consts.VAR_SOURCE_CONFIG),
+		fileVars,
+		vars.FromEnv(os.Environ()),
+		builtinVars,
+		flagVars,
+	), nil
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/sources"
+	"github-project-template/internal/trust"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// trustCmd groups the subcommands managing the templates trusted to run hooks.
+	trustCmd = &cobra.Command{
+		Use:   "trust",
+		Short: "Manage templates trusted to run hooks",
+		Long:  "Manage the templates whose hooks run without confirmation.\nTemplates are identified by their repository, e.g. acme/templates (prefixed with the host for instances other than github.com), or the path of a local directory or archive,\ntogether with the commit SHA they were read at, or the sha256 of the contents of a directory or archive.\nOmitting the SHA trusts every revision of a repository; directories and archives are only trusted at their sha256.",
+	}
+	// trustAddCmd trusts a template.
+	trustAddCmd = &cobra.Command{
+		Use:   "add <source> [sha]",
+		Short: "Trust a template to run hooks",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  runTrustAdd,
+	}
+	// trustListCmd lists the trusted templates.
+	trustListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the templates trusted to run hooks",
+		Args:  cobra.NoArgs,
+		RunE:  runTrustList,
+	}
+	// trustRemoveCmd stops trusting a template.
+	trustRemoveCmd = &cobra.Command{
+		Use:   "remove <source> [sha]",
+		Short: "Stop trusting a template to run hooks",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  runTrustRemove,
+	}
+)
+
+// init registers the trust command and its subcommands with the root command.
+// Parameters: None.
+func init() {
+	trustCmd.AddCommand(trustAddCmd, trustListCmd, trustRemoveCmd)
+	RootCmd.AddCommand(trustCmd)
+}
+
+// runTrustAdd adds a template, optionally at a single SHA, to the trust store.
+// Local directories and archives can only be trusted at the content hash they are identified by.
+// Parameters:
+// - cmd: A pointer to the Cobra command being executed.
+// - args: The source and the optional SHA.
+// Returns: An error if the trust store could not be read or saved.
+func runTrustAdd(cmd *cobra.Command, args []string) error {
+	store, err := loadTrustStore()
+	if err != nil {
+		return err
+	}
+
+	entry := trust.Entry{Source: trustSource(args[0]), SHA: optionalArg(args, 1)}
+	if entry.SHA == consts.EMPTY_STRING && isLocalTemplate(entry.Source) {
+		return fmt.Errorf("%s is a local directory or archive whose contents can change; trust it at the sha256 shown when its hooks are confirmed", entry.Source)
+	}
+	if !store.Add(entry.Source, entry.SHA) {
+		fmt.Printf("%s is already trusted\n", entry)
+		return nil
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Trusted %s\n", entry)
+	return nil
+}
+
+// runTrustList prints every trusted template.
+// Parameters:
+// - cmd: A pointer to the Cobra command being executed.
+// - args: Unused.
+// Returns: An error if the trust store could not be read.
+func runTrustList(cmd *cobra.Command, args []string) error {
+	store, err := loadTrustStore()
+	if err != nil {
+		return err
+	}
+
+	if len(store.Trusted) == 0 {
+		fmt.Println("no trusted templates")
+		return nil
+	}
+	for _, entry := range store.Trusted {
+		fmt.Println(entry)
+	}
+	return nil
+}
+
+// runTrustRemove removes a template, at a single SHA or at every SHA, from the trust store.
+// Parameters:
+// - cmd: A pointer to the Cobra command being executed.
+// - args: The source and the optional SHA.
+// Returns: An error if the template was not trusted or the trust store could not be read or saved.
+func runTrustRemove(cmd *cobra.Command, args []string) error {
+	store, err := loadTrustStore()
+	if err != nil {
+		return err
+	}
+
+	source, sha := trustSource(args[0]), optionalArg(args, 1)
+	removed := store.Remove(source, sha)
+	if removed == 0 {
+		return fmt.Errorf("%s is not trusted", trust.Entry{Source: source, SHA: sha})
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed %d trusted entries of %s\n", removed, source)
+	return nil
+}
+
+// trustFile returns the path of the trust store: the trust-file configuration value (REPO_STUB_TRUST_FILE), or $HOME/.repo-stub-trust.yaml.
+// Returns: The path of the trust store and an error if the home directory is unknown.
+func trustFile() (string, error) {
+	if path := viper.GetString("trust-file"); path != consts.EMPTY_STRING {
+		return path, nil
+	}
+	return trust.DefaultPath()
+}
+
+// loadTrustStore reads the trust store from trustFile.
+// Returns: A pointer to the trust.Store and an error if it could not be read.
+func loadTrustStore() (*trust.Store, error) {
+	path, err := trustFile()
+	if err != nil {
+		return nil, err
+	}
+	return trust.Load(path)
+}
+
+// trustSource normalizes a source given on the command line the way template origins are recorded:
+// existing local directories and archives by their absolute path, anything else as given.
+// Parameters:
+// - source: The source as given, e.g. acme/templates or ./templates.
+// Returns: The normalized source.
+func trustSource(source string) string {
+	if _, err := os.Stat(source); err != nil {
+		return source
+	}
+	if abs, err := filepath.Abs(source); err == nil {
+		return abs
+	}
+	return source
+}
+
+// isLocalTemplate reports whether a normalized source names a local directory or an archive.
+// Parameters:
+// - source: The source as normalized by trustSource.
+// Returns: true if the source is identified by a content hash rather than a commit.
+func isLocalTemplate(source string) bool {
+	if sources.IsArchive(source) {
+		return true
+	}
+	info, err := os.Stat(source)
+	return err == nil && info.IsDir()
+}
+
+// optionalArg returns the argument at index i, or an empty string when there are not that many arguments.
+func optionalArg(args []string, i int) string {
+	if len(args) > i {
+		return args[i]
+	}
+	return consts.EMPTY_STRING
+}
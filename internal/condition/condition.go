@@ -0,0 +1,141 @@
+package condition
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/render"
+)
+
+// Expression is a parsed condition, e.g. language == "go" && include_docker.
+// The empty expression is always true.
+type Expression struct {
+	tmpl *template.Template
+}
+
+// Parse parses a condition. Conditions combine variables, string, number and boolean literals with
+// the comparisons ==, !=, <, <=, > and >=, the operators !, && and || and parentheses, e.g. language == "go" && include_docker.
+// Any other condition is a text/template pipeline, as used in {{ if }} actions, with the template functions of
+// render.FuncMap, e.g. eq .language "go" or hasPrefix "go" .language.
+// The operators and the and, or and not functions, like the condition itself, count a value as true unless it is false, zero, empty, not set,
+// or a string reading false, no or 0, so --var include_docker=false turns a condition off.
+// Parameters:
+// - expression: The condition to parse, e.g. language == "go" && include_docker.
+// Returns: The parsed Expression and an error if the condition is not valid.
+func Parse(expression string) (*Expression, error) {
+	if strings.TrimSpace(expression) == consts.EMPTY_STRING {
+		return &Expression{}, nil
+	}
+
+	pipeline := expression
+	if tokens, err := tokenize(expression); err == nil && isOperatorExpression(tokens) {
+		if pipeline, err = translate(tokens); err != nil {
+			return nil, fmt.Errorf("invalid condition %q: %v", expression, err)
+		}
+	}
+
+	tmpl, err := template.New("when").
+		Funcs(render.FuncMap()).
+		Funcs(template.FuncMap{"truthy": truthy, "and": and, "or": or, "not": not, "compare": compare}).
+		// variables that are not set are nil, and so false
+		Option("missingkey=zero").
+		Parse(fmt.Sprintf("{{ if truthy (%s) }}true{{ end }}", pipeline))
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %v", expression, err)
+	}
+	return &Expression{tmpl: tmpl}, nil
+}
+
+// Eval parses and evaluates a condition with the given variables.
+// Parameters:
+// - expression: The condition to evaluate; the empty condition is true.
+// - data: The variables the condition refers to.
+// Returns: The result of the condition and an error if it is not valid or could not be evaluated.
+func Eval(expression string, data map[string]any) (bool, error) {
+	e, err := Parse(expression)
+	if err != nil {
+		return false, err
+	}
+	return e.Eval(data)
+}
+
+// Eval evaluates the expression with the given variables.
+// Parameters:
+// - data: The variables the expression refers to.
+// Returns: The result of the expression and an error if it could not be evaluated, e.g. when it orders values of different types.
+func (e *Expression) Eval(data map[string]any) (bool, error) {
+	if e.tmpl == nil {
+		return true, nil
+	}
+
+	var out bytes.Buffer
+	if err := e.tmpl.Execute(&out, data); err != nil {
+		return false, fmt.Errorf("failed to evaluate condition: %v", err)
+	}
+	return out.String() == "true", nil
+}
+
+// truthy reports whether a value counts as true: everything but nil, false, zero, empty values
+// and the strings false, no and 0 in any case, which is how --var and environment variables spell false.
+func truthy(value any) bool {
+	if s, ok := value.(string); ok {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case consts.EMPTY_STRING, "false", "no", "0":
+			return false
+		}
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() > 0
+	case reflect.Pointer, reflect.Interface:
+		return !v.IsNil()
+	}
+	return !v.IsZero()
+}
+
+// and returns the first argument that is not truthy, or the last argument, like the and template function.
+func and(first any, rest ...any) any {
+	if !truthy(first) {
+		return first
+	}
+	for _, arg := range rest {
+		if !truthy(arg) {
+			return arg
+		}
+	}
+	if len(rest) == 0 {
+		return first
+	}
+	return rest[len(rest)-1]
+}
+
+// or returns the first argument that is truthy, or the last argument, like the or template function.
+func or(first any, rest ...any) any {
+	if truthy(first) {
+		return first
+	}
+	for _, arg := range rest {
+		if truthy(arg) {
+			return arg
+		}
+	}
+	if len(rest) == 0 {
+		return first
+	}
+	return rest[len(rest)-1]
+}
+
+// not returns the boolean negation of its argument.
+func not(value any) bool {
+	return !truthy(value)
+}
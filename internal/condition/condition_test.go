@@ -0,0 +1,108 @@
+package condition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	data := map[string]any{
+		"language":       "go",
+		"include_docker": true,
+		"include_ci":     false,
+		"port":           8080,
+		"author":         "",
+		"from_flag":      "false",
+		"answer":         "No",
+		"zero":           "0",
+		"enabled":        "yes",
+		"tags":           []string{},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   bool
+	}{
+		{"Empty", "", true},
+		{"Variable", ".include_docker", true},
+		{"False variable", ".include_ci", false},
+		{"Empty string", ".author", false},
+		{"False string", ".from_flag", false},
+		{"No string", ".answer", false},
+		{"Zero string", ".zero", false},
+		{"Other string", ".enabled", true},
+		{"Empty list", ".tags", false},
+		{"Unset variable", ".missing", false},
+		{"Equal", `eq .language "go"`, true},
+		{"Equal unset variable", `eq .missing "go"`, false},
+		{"Not equal", `ne .language "go"`, false},
+		{"And", `and (eq .language "go") .include_docker`, true},
+		{"And false", `and (eq .language "go") .include_ci`, false},
+		{"And false string", `and .include_docker .from_flag`, false},
+		{"Or", `or .include_ci (eq .language "go")`, true},
+		{"Or false strings", `or .from_flag .answer`, false},
+		{"Not", "not .include_ci", true},
+		{"Not false string", "not .from_flag", true},
+		{"Not unset variable", "not .missing", true},
+		{"Nested", `and (not (or .include_ci (eq .language "python"))) .include_docker`, true},
+		{"Number", "and (ge .port 1024) (lt .port 65536)", true},
+		{"Pipeline", `.language | eq "go"`, true},
+		{"Operators", `language == "go" && include_docker`, true},
+		{"Operators false", `language == "go" && include_ci`, false},
+		{"Bare variable", "include_docker", true},
+		{"Parenthesized variable", "(include_docker)", true},
+		{"Bare unset variable", "missing", false},
+		{"Bare false string", "from_flag", false},
+		{"Not equal operator", `language != "go"`, false},
+		{"Single quoted string", `language == 'go'`, true},
+		{"Or operator", `include_ci || language == "go"`, true},
+		{"Not operator", "!include_ci", true},
+		{"Not operator on false string", "!from_flag && !answer && !zero", true},
+		{"Not operator on unset variable", "!missing", true},
+		{"Precedence", `include_ci || include_docker && language == "go"`, true},
+		{"Parentheses", `(include_ci || include_docker) && !(language == "python")`, true},
+		{"Number comparison", "port >= 1024 && port < 65536", true},
+		{"Number equality", "port == 8080", true},
+		{"Boolean literal", "include_docker == true", true},
+		{"Unset variable comparison", `missing == "go"`, false},
+		{"Dotted variable", `.language == "go"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Eval(tt.expression, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	data := map[string]any{"language": "go", "port": 8080}
+
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"Unknown function", "missing .language"},
+		{"Ordering strings", `language < "rust"`},
+		{"Missing operand", `language ==`},
+		{"Missing closing parenthesis", `(language == "go"`},
+		{"Unexpected token", `language == "go" include_docker`},
+		{"Unterminated string operand", `language == "go`},
+		{"Comparing different types", `lt .port "rust"`},
+		{"Unterminated string", `eq .language "go`},
+		{"Unbalanced parentheses", "(eq .language"},
+		{"Missing argument", "eq .language"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Eval(tt.expression, data)
+			assert.Error(t, err)
+		})
+	}
+}
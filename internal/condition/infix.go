@@ -0,0 +1,296 @@
+package condition
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github-project-template/internal/consts"
+)
+
+// token is a lexical token of an operator expression.
+type token struct {
+	kind int
+	text string
+}
+
+// Kinds of tokens.
+const (
+	tokenIdent = iota
+	tokenField
+	tokenString
+	tokenNumber
+	tokenOp
+)
+
+// isOperatorExpression reports whether tokens spell an operator expression, e.g. language == "go" && include_docker,
+// rather than a pipeline: it uses one of the operators, or is a single, possibly parenthesized, variable or literal.
+func isOperatorExpression(tokens []token) bool {
+	operands := 0
+	for _, t := range tokens {
+		switch {
+		case t.kind != tokenOp:
+			operands++
+		case t.text != "(" && t.text != ")":
+			return true
+		}
+	}
+	return operands == 1
+}
+
+// translate converts an operator expression into the equivalent template pipeline, e.g.
+// language == "go" && include_docker into and (compare "==" .language "go") .include_docker.
+// Parameters:
+// - tokens: The tokens of the expression.
+// Returns: The pipeline and an error if the expression is not valid.
+func translate(tokens []token) (string, error) {
+	p := &parser{tokens: tokens}
+	pipeline, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos].text)
+	}
+	return pipeline, err
+}
+
+// tokenize splits an expression into tokens.
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			text := string(runes[i : end+1])
+			if r == '\'' {
+				text = `"` + strings.ReplaceAll(text[1:len(text)-1], `"`, `\"`) + `"`
+			}
+			unquoted, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", string(runes[i:end+1]))
+			}
+			tokens = append(tokens, token{kind: tokenString, text: unquoted})
+			i = end + 1
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_' || r == '.':
+			kind := tokenIdent
+			end := i
+			if r == '.' {
+				kind = tokenField
+				end++
+			}
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			if end == i+1 && kind == tokenField {
+				return nil, fmt.Errorf("unexpected .")
+			}
+			tokens = append(tokens, token{kind: kind, text: strings.TrimPrefix(string(runes[i:end]), ".")})
+			i = end
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch pair := string(runes[i : i+2]); pair {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = pair
+				}
+			}
+			switch op {
+			case "==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")":
+			default:
+				return nil, fmt.Errorf("unexpected %s", op)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a recursive descent parser translating the tokens of an operator expression into a pipeline.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// accept consumes the next token if it is one of the given operators.
+func (p *parser) accept(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenOp {
+		return consts.EMPTY_STRING, false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return consts.EMPTY_STRING, false
+}
+
+// parseOr parses operands joined by ||.
+func (p *parser) parseOr() (string, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return consts.EMPTY_STRING, err
+		}
+		left = fmt.Sprintf("(or %s %s)", left, right)
+	}
+}
+
+// parseAnd parses operands joined by &&.
+func (p *parser) parseAnd() (string, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return consts.EMPTY_STRING, err
+		}
+		left = fmt.Sprintf("(and %s %s)", left, right)
+	}
+}
+
+// parseNot parses an operand negated by any number of !.
+func (p *parser) parseNot() (string, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return consts.EMPTY_STRING, err
+		}
+		return fmt.Sprintf("(not %s)", operand), nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses an operand optionally compared with another.
+func (p *parser) parseComparison() (string, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	return fmt.Sprintf("(compare %q %s %s)", op, left, right), nil
+}
+
+// parsePrimary parses a variable, a literal or a parenthesized expression.
+func (p *parser) parsePrimary() (string, error) {
+	if _, ok := p.accept("("); ok {
+		inner, err := p.parseOr()
+		if err != nil {
+			return consts.EMPTY_STRING, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return consts.EMPTY_STRING, fmt.Errorf("missing )")
+		}
+		return inner, nil
+	}
+	if p.pos >= len(p.tokens) {
+		return consts.EMPTY_STRING, fmt.Errorf("unexpected end of condition")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokenString:
+		return strconv.Quote(t.text), nil
+	case tokenNumber:
+		if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+			return consts.EMPTY_STRING, fmt.Errorf("invalid number %s", t.text)
+		}
+		return t.text, nil
+	case tokenIdent:
+		if t.text == "true" || t.text == "false" {
+			return t.text, nil
+		}
+		return "." + t.text, nil
+	case tokenField:
+		return "." + t.text, nil
+	default:
+		return consts.EMPTY_STRING, fmt.Errorf("unexpected %s", t.text)
+	}
+}
+
+// compare compares two values with one of the operators ==, !=, <, <=, > and >=.
+// Numbers are compared by value, whatever their type; any other values are only equal when they print the same, and cannot be ordered.
+func compare(op string, left, right any) (bool, error) {
+	l, lok := number(left)
+	r, rok := number(right)
+	if !lok || !rok {
+		switch op {
+		case "==":
+			return fmt.Sprint(left) == fmt.Sprint(right), nil
+		case "!=":
+			return fmt.Sprint(left) != fmt.Sprint(right), nil
+		default:
+			return false, fmt.Errorf("cannot compare %v %s %v, only numbers can be ordered", left, op, right)
+		}
+	}
+
+	switch op {
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	default:
+		return l >= r, nil
+	}
+}
+
+// number converts numeric values to float64.
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
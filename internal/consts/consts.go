@@ -35,6 +35,12 @@ const (
 	// FILE_TYPE represents a file type identifier.
 	FILE_TYPE = "file"
 
+	// BLOB_TYPE represents a file in a git tree.
+	BLOB_TYPE = "blob"
+
+	// TREE_TYPE represents a directory in a git tree.
+	TREE_TYPE = "tree"
+
 	// GIT_HUB represents the directory name for the github files.
 	GIT_HUB = ".github"
 
@@ -59,12 +65,21 @@ const (
 	//-TODO represents the filename for TODO files.
 	TODO = "TODO"
 
+	// TMPL represents the extension marking files that are rendered through text/template, stripped on write.
+	TMPL = ".tmpl"
+
 	// VSCODE represents the directory name for the VSCODE files.
 	VSCODE = ".vscode"
 
 	// VERSION_GO represents the filename for the version file for go.
 	VERSION_GO = "version.go"
 
+	// BUMPVERSION represents the filename for the python release file, written as .bumpversion.cfg.
+	BUMPVERSION = "bumpversion.cfg"
+
+	// VERSION_PY represents the filename for the version file for python.
+	VERSION_PY = "__version__.py"
+
 	// WORKFLOW represents the directory name for the workflow files.
 	WORKFLOW = "workflows"
 
@@ -101,3 +116,132 @@ const (
 	// WORKFLOW_FILES represents files related to CI/CD workflows.
 	WORKFLOW_FLIES = ".workflowFiles"
 )
+
+// Fetch modes used to download the template from GitHub.
+const (
+	// FETCH_CONTENTS walks the repository one directory at a time through the Contents API.
+	FETCH_CONTENTS = "contents"
+
+	// FETCH_TARBALL downloads the repository tarball once and reads the template from memory.
+	FETCH_TARBALL = "tarball"
+
+	// FETCH_TREE resolves the whole template layout through the Git Trees API and fetches blobs on demand.
+	FETCH_TREE = "tree"
+)
+
+// Providers that can host the template repository.
+const (
+	// PROVIDER_GITHUB reads the template from GitHub.
+	PROVIDER_GITHUB = "github"
+
+	// PROVIDER_GITLAB reads the template from GitLab.
+	PROVIDER_GITLAB = "gitlab"
+
+	// PROVIDER_GITEA reads the template from a Gitea instance.
+	PROVIDER_GITEA = "gitea"
+
+	// PROVIDER_FORGEJO reads the template from a Forgejo instance, which shares the Gitea API.
+	PROVIDER_FORGEJO = "forgejo"
+
+	// GITLAB_API_URL is the default GitLab instance used when no API URL is given.
+	GITLAB_API_URL = "https://gitlab.com"
+
+	// GITHUB_API_URL is the default GitHub API used when no API URL is given.
+	GITHUB_API_URL = "https://api.github.com"
+
+	// GITHUB_API_HOST is the host of the public GitHub API.
+	GITHUB_API_HOST = "api.github.com"
+
+	// GITHUB_RAW_HOST is the host serving raw file downloads for github.com.
+	GITHUB_RAW_HOST = "raw.githubusercontent.com"
+
+	// GITHUB_CODELOAD_HOST is the host serving repository archives for github.com.
+	GITHUB_CODELOAD_HOST = "codeload.github.com"
+)
+
+// Sources of template variable values, listed from lowest to highest precedence; defaults and prompts only fill in variables without a value.
+const (
+	// VAR_SOURCE_TEMPLATE marks a built-in value taken from the template reference, e.g. the owner acme of --template acme/templates.
+	VAR_SOURCE_TEMPLATE = "template"
+
+	// VAR_SOURCE_CONFIG marks a value from the vars map of the configuration file, or a flag value set in the configuration file.
+	VAR_SOURCE_CONFIG = "config"
+
+	// VAR_SOURCE_FILE marks a value from the file passed with --vars-file.
+	VAR_SOURCE_FILE = "file"
+
+	// VAR_SOURCE_ENV marks a value from a REPO_STUB_VAR_* environment variable, or a flag value set by a REPO_STUB_* environment variable.
+	VAR_SOURCE_ENV = "env"
+
+	// VAR_SOURCE_FLAG marks a value passed with --var.
+	VAR_SOURCE_FLAG = "flag"
+
+	// VAR_SOURCE_PROMPT marks a value answered at an interactive prompt.
+	VAR_SOURCE_PROMPT = "prompt"
+
+	// VAR_SOURCE_DEFAULT marks the default of a variable declared by the template manifest, used when no value was given.
+	VAR_SOURCE_DEFAULT = "default"
+
+	// VAR_ENV_PREFIX is the prefix of environment variables holding template variables, e.g. REPO_STUB_VAR_AUTHOR sets author.
+	VAR_ENV_PREFIX = "REPO_STUB_VAR_"
+)
+
+// Template manifest file and the names of the categories of the built-in default manifest.
+const (
+	// MANIFEST is the filename of the template manifest at the template root.
+	MANIFEST = "stub.yaml"
+
+	// CATEGORY_IGNORE names the category of ignore files.
+	CATEGORY_IGNORE = "ignore"
+
+	// CATEGORY_LICENSE names the category of license files.
+	CATEGORY_LICENSE = "license"
+
+	// CATEGORY_MAKEFILE names the optional category of Makefiles, included with --include-makefile.
+	CATEGORY_MAKEFILE = "makefile"
+
+	// CATEGORY_README names the category of README files.
+	CATEGORY_README = "readme"
+
+	// CATEGORY_RELEASE names the category of release configuration files.
+	CATEGORY_RELEASE = "release"
+
+	// CATEGORY_TODO names the category of TODO files.
+	CATEGORY_TODO = "todo"
+
+	// CATEGORY_VERSION names the optional category of version files, included with --include-version-file.
+	CATEGORY_VERSION = "version"
+
+	// CATEGORY_VSCODE names the category of Visual Studio Code configuration files.
+	CATEGORY_VSCODE = "vscode"
+
+	// CATEGORY_WORKFLOWS names the category of CI/CD workflow files.
+	CATEGORY_WORKFLOWS = "workflows"
+)
+
+// Types of the variables declared by the template manifest.
+const (
+	// VAR_TYPE_STRING is the type of text variables, the default.
+	VAR_TYPE_STRING = "string"
+
+	// VAR_TYPE_BOOL is the type of true/false variables.
+	VAR_TYPE_BOOL = "bool"
+
+	// VAR_TYPE_INT is the type of integer variables.
+	VAR_TYPE_INT = "int"
+
+	// VAR_FORMAT_SEMVER is the format of variables holding a semantic version, e.g. 1.23 or v2.1.0.
+	VAR_FORMAT_SEMVER = "semver"
+)
+
+// Stages of the hooks declared by the template manifest.
+const (
+	// HOOK_PRE is the stage of the hooks run before the template is written.
+	HOOK_PRE = "pre"
+
+	// HOOK_POST is the stage of the hooks run after the template is written.
+	HOOK_POST = "post"
+)
+
+// TRUST_FILE is the filename of the store of templates trusted to run hooks, kept in the home directory.
+const TRUST_FILE = ".repo-stub-trust.yaml"
@@ -0,0 +1,154 @@
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github-project-template/internal/consts"
+)
+
+// Options describes the repository and initial commit created by Init.
+type Options struct {
+	// Branch is the name of the initial branch, e.g. main.
+	Branch string
+	// Message is the message of the initial commit.
+	Message string
+	// Author is the author and committer of the initial commit.
+	Author Author
+}
+
+// Author identifies the author of a commit.
+type Author struct {
+	// Name is the name of the author.
+	Name string
+	// Email is the email address of the author.
+	Email string
+}
+
+// ResolveAuthor returns the author of the initial commit: the given name and email, falling back to user.name and user.email
+// of the global and then the system git configuration.
+// Parameters:
+// - name: The name of the author, or empty to read it from the git configuration.
+// - email: The email address of the author, or empty to read it from the git configuration.
+// Returns: The Author and an error if the name or email is not set anywhere.
+func ResolveAuthor(name, email string) (Author, error) {
+	author := Author{Name: name, Email: email}
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		if author.Name != consts.EMPTY_STRING && author.Email != consts.EMPTY_STRING {
+			break
+		}
+
+		cfg, err := config.LoadConfig(scope)
+		if err != nil {
+			continue
+		}
+		if author.Name == consts.EMPTY_STRING {
+			author.Name = cfg.User.Name
+		}
+		if author.Email == consts.EMPTY_STRING {
+			author.Email = cfg.User.Email
+		}
+	}
+
+	if author.Name == consts.EMPTY_STRING || author.Email == consts.EMPTY_STRING {
+		return Author{}, fmt.Errorf("the author of the initial commit is unknown; set user.name and user.email in your git config or pass --git-author-name and --git-author-email")
+	}
+	return author, nil
+}
+
+// Containing returns the root of the git repository containing dir, which does not need to exist yet.
+// Parameters:
+// - dir: The directory to look up.
+// Returns: The root of the repository, empty when dir is not inside one, and an error if a repository could not be opened.
+func Containing(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to resolve %s: %v", dir, err)
+	}
+
+	// start from the closest directory that exists
+	for {
+		if _, err := os.Stat(abs); err == nil {
+			break
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return consts.EMPTY_STRING, nil
+		}
+		abs = parent
+	}
+
+	repo, err := git.PlainOpenWithOptions(abs, &git.PlainOpenOptions{DetectDotGit: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return consts.EMPTY_STRING, nil
+	}
+	if err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to open the git repository containing %s: %v", dir, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		// a bare repository has no work tree the directory could be part of
+		return consts.EMPTY_STRING, nil
+	}
+	return worktree.Filesystem.Root(), nil
+}
+
+// Init turns dir into a git repository and creates an initial commit containing exactly the given files.
+// Parameters:
+// - dir: The directory to initialize.
+// - files: The paths of the files to commit, which must be inside dir.
+// - opts: The initial branch, commit message and author.
+// Returns: The hash of the initial commit and an error if the repository could not be created or the files could not be committed.
+func Init(dir string, files []string, opts Options) (string, error) {
+	if len(files) == 0 {
+		return consts.EMPTY_STRING, fmt.Errorf("no files were written, so there is nothing to commit")
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to resolve %s: %v", dir, err)
+	}
+
+	repo, err := git.PlainInitWithOptions(root, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName(opts.Branch)},
+	})
+	if err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to initialize a git repository in %s: %v", dir, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to open the work tree of %s: %v", dir, err)
+	}
+
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return consts.EMPTY_STRING, fmt.Errorf("failed to resolve %s: %v", file, err)
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return consts.EMPTY_STRING, fmt.Errorf("%s is not inside %s", file, dir)
+		}
+		if _, err := worktree.Add(filepath.ToSlash(rel)); err != nil {
+			return consts.EMPTY_STRING, fmt.Errorf("failed to add %s: %v", file, err)
+		}
+	}
+
+	signature := &object.Signature{Name: opts.Author.Name, Email: opts.Author.Email, When: time.Now()}
+	hash, err := worktree.Commit(opts.Message, &git.CommitOptions{Author: signature, Committer: signature})
+	if err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to create the initial commit: %v", err)
+	}
+	return hash.String(), nil
+}
@@ -0,0 +1,102 @@
+package gitrepo
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "README.md"), filepath.Join(dir, "cmd", "app", "main.go")}
+	for _, file := range append(files, filepath.Join(dir, "go.sum")) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte(filepath.Base(file)), 0644))
+	}
+
+	hash, err := Init(dir, files, Options{Branch: "trunk", Message: "Stub project", Author: Author{Name: "Jane Doe", Email: "jane@example.com"}})
+	require.NoError(t, err)
+
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/trunk", head.Name().String())
+	assert.Equal(t, hash, head.Hash().String())
+
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "Stub project", commit.Message)
+	assert.Equal(t, "Jane Doe", commit.Author.Name)
+	assert.Equal(t, "jane@example.com", commit.Committer.Email)
+
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+	var committed []string
+	require.NoError(t, tree.Files().ForEach(func(f *object.File) error {
+		committed = append(committed, f.Name)
+		return nil
+	}))
+	sort.Strings(committed)
+	assert.Equal(t, []string{"README.md", "cmd/app/main.go"}, committed)
+
+	_, err = Init(dir, files, Options{Branch: "main", Message: "again", Author: Author{Name: "Jane", Email: "jane@example.com"}})
+	assert.Error(t, err)
+}
+
+func TestInitErrors(t *testing.T) {
+	author := Author{Name: "Jane", Email: "jane@example.com"}
+
+	_, err := Init(t.TempDir(), nil, Options{Branch: "main", Message: "init", Author: author})
+	assert.ErrorContains(t, err, "nothing to commit")
+
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("x"), 0644))
+	_, err = Init(t.TempDir(), []string{outside}, Options{Branch: "main", Message: "init", Author: author})
+	assert.ErrorContains(t, err, "is not inside")
+}
+
+func TestContaining(t *testing.T) {
+	dir := t.TempDir()
+	root, err := Containing(filepath.Join(dir, "new", "project"))
+	require.NoError(t, err)
+	assert.Empty(t, root)
+
+	_, err = git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	root, err = Containing(filepath.Join(dir, "new", "project"))
+	require.NoError(t, err)
+	assert.Equal(t, dir, root)
+}
+
+func TestResolveAuthor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	author, err := ResolveAuthor("Jane", "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, Author{Name: "Jane", Email: "jane@example.com"}, author)
+
+	if _, err := os.Stat("/etc/gitconfig"); err != nil {
+		_, err = ResolveAuthor("Jane", "")
+		assert.Error(t, err)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[user]\n\tname = Jane Doe\n\temail = jane@example.com\n"), 0644))
+	author, err = ResolveAuthor("", "")
+	require.NoError(t, err)
+	assert.Equal(t, Author{Name: "Jane Doe", Email: "jane@example.com"}, author)
+
+	author, err = ResolveAuthor("Someone Else", "")
+	require.NoError(t, err)
+	assert.Equal(t, Author{Name: "Someone Else", Email: "jane@example.com"}, author)
+}
@@ -0,0 +1,136 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/gookit/color"
+
+	"github-project-template/internal/condition"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/render"
+)
+
+// Command is a hook ready to run, with its command, working directory and environment rendered.
+type Command struct {
+	// Stage is the stage the hook belongs to, pre or post.
+	Stage string
+	// Run is the rendered shell command.
+	Run string
+	// Dir is the working directory of the command.
+	Dir string
+	// Env are the additional environment variables of the command as KEY=value, sorted by key.
+	Env []string
+}
+
+// String formats the command as it is shown before it runs, e.g. post: go mod tidy (in out/app).
+func (c Command) String() string {
+	s := fmt.Sprintf("%s: %s", c.Stage, c.Run)
+	for _, env := range c.Env {
+		s = fmt.Sprintf("%s [%s]", s, env)
+	}
+	return fmt.Sprintf("%s (in %s)", s, c.Dir)
+}
+
+// Prepare renders the hooks of a stage whose conditions hold, so they can be shown and run.
+// Parameters:
+// - stage: The stage of the hooks, pre or post.
+// - hooks: The hooks declared by the template manifest for the stage.
+// - outputDir: The output directory the hooks run in.
+// - data: The values the conditions are evaluated and the commands rendered with.
+// Returns: The commands to run, in order, and an error if a condition could not be evaluated or a hook could not be rendered.
+func Prepare(stage string, hooks []manifest.Hook, outputDir string, data render.Data) ([]Command, error) {
+	var commands []Command
+	for i, hook := range hooks {
+		ok, err := condition.Eval(hook.When, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s hook %d: %v", stage, i+1, err)
+		}
+		if !ok {
+			continue
+		}
+
+		command, err := prepare(stage, hook, outputDir, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s hook %d: %v", stage, i+1, err)
+		}
+		commands = append(commands, command)
+	}
+	return commands, nil
+}
+
+// prepare renders the command, working directory and environment of a single hook.
+// Parameters:
+// - stage: The stage of the hook, pre or post.
+// - hook: The hook declared by the template manifest.
+// - outputDir: The output directory the working directory is relative to.
+// - data: The values the hook is rendered with.
+// Returns: The command to run and an error if a part of the hook could not be rendered.
+func prepare(stage string, hook manifest.Hook, outputDir string, data render.Data) (Command, error) {
+	run, err := render.Render("run", []byte(hook.Run), data)
+	if err != nil {
+		return Command{}, err
+	}
+
+	dir, err := render.Render("dir", []byte(hook.Dir), data)
+	if err != nil {
+		return Command{}, err
+	}
+
+	keys := make([]string, 0, len(hook.Env))
+	for key := range hook.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var env []string
+	for _, key := range keys {
+		value, err := render.Render(key, []byte(hook.Env[key]), data)
+		if err != nil {
+			return Command{}, err
+		}
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return Command{
+		Stage: stage,
+		Run:   string(run),
+		Dir:   filepath.Join(outputDir, filepath.FromSlash(string(dir))),
+		Env:   env,
+	}, nil
+}
+
+// Run runs the commands in order through the shell, streaming their output, and stops at the first one that fails.
+// Parameters:
+// - commands: The commands to run.
+// - stdout: Where the output of the commands is streamed to.
+// - stderr: Where the error output of the commands is streamed to.
+// Returns: An error naming the command that failed.
+func Run(commands []Command, stdout, stderr io.Writer) error {
+	for _, command := range commands {
+		fmt.Fprintf(stdout, "Running %s hook: %s\n", command.Stage, color.New(color.FgCyan).Sprint(command.Run))
+
+		cmd := shell(command.Run)
+		cmd.Dir = command.Dir
+		cmd.Env = append(os.Environ(), command.Env...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %v", command.Stage, command.Run, err)
+		}
+	}
+	return nil
+}
+
+// shell creates the command running the given command line through the shell of the operating system.
+func shell(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/render"
+)
+
+func TestPrepare(t *testing.T) {
+	data := render.Data{render.MODULE: "github.com/acme/app", render.LANGUAGE: "go", "include_docker": false}
+	declared := []manifest.Hook{
+		{Run: "go mod init {{ .module }}", When: `eq .language "go"`},
+		{Run: "docker build .", When: ".include_docker"},
+		{Run: "chmod +x *", Dir: "scripts", Env: map[string]string{"B": "2", "A": "{{ .language }}"}},
+	}
+
+	commands, err := Prepare(consts.HOOK_POST, declared, "out", data)
+	require.NoError(t, err)
+	assert.Equal(t, []Command{
+		{Stage: consts.HOOK_POST, Run: "go mod init github.com/acme/app", Dir: "out"},
+		{Stage: consts.HOOK_POST, Run: "chmod +x *", Dir: filepath.Join("out", "scripts"), Env: []string{"A=go", "B=2"}},
+	}, commands)
+	assert.Equal(t, "post: chmod +x * [A=go] [B=2] (in "+filepath.Join("out", "scripts")+")", commands[1].String())
+
+	_, err = Prepare(consts.HOOK_PRE, []manifest.Hook{{Run: "echo", When: `lt .language 1`}}, "out", data)
+	assert.ErrorContains(t, err, "pre hook 1")
+
+	_, err = Prepare(consts.HOOK_PRE, []manifest.Hook{{Run: "echo {{ .missing }}"}}, "out", data)
+	assert.ErrorContains(t, err, "pre hook 1")
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands use a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+	err := Run([]Command{
+		{Stage: consts.HOOK_POST, Run: `echo "$GREETING" > hello.txt && echo done`, Dir: dir, Env: []string{"GREETING=hi"}},
+		{Stage: consts.HOOK_POST, Run: "echo oops >&2"},
+	}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "hello.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hi\n", string(content))
+	assert.Contains(t, stdout.String(), "done")
+	assert.Equal(t, "oops\n", stderr.String())
+
+	stdout.Reset()
+	err = Run([]Command{
+		{Stage: consts.HOOK_PRE, Run: "exit 3", Dir: dir},
+		{Stage: consts.HOOK_PRE, Run: "echo never", Dir: dir},
+	}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `pre hook "exit 3" failed`)
+	assert.NotContains(t, stdout.String(), "never")
+}
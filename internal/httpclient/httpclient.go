@@ -3,6 +3,7 @@ package httpclient
 import (
 	"github-project-template/internal/consts"
 	"net/http"
+	"strings"
 )
 
 // Client is a global variable holding the HTTP client used for making requests with authentication support.
@@ -17,9 +18,23 @@ type HTTPClient interface {
 
 var Client HTTPClient
 
+// AuthHeader selects the header the authentication token is sent in.
+type AuthHeader string
+
+const (
+	// AuthorizationHeader sends the token as "Authorization: token <token>", as expected by GitHub and Gitea.
+	AuthorizationHeader AuthHeader = "Authorization"
+	// PrivateTokenHeader sends the token as "PRIVATE-TOKEN: <token>", as expected by GitLab.
+	PrivateTokenHeader AuthHeader = "PRIVATE-TOKEN"
+)
+
 type transportWithAuth struct {
 	// authToken is the authentication token used for authorized requests.
 	authToken string
+	// header is the header the authentication token is sent in.
+	header AuthHeader
+	// hosts limits the hosts, and their subdomains, the token is sent to. An empty list sends it to every host.
+	hosts []string
 	// rt is the underlying RoundTripper used for HTTP transport.
 	rt http.RoundTripper
 }
@@ -29,12 +44,25 @@ type transportWithAuth struct {
 // Parameters:
 // - authToken: The authentication token to be used for authorized requests.
 // Returns:
-// - A pointer to the initialized http.Client.
 // - An error if any issues occur during client initialization (returns nil in this implementation).
 func InitClient(authToken string) error {
+	return InitClientWithHeader(authToken, AuthorizationHeader)
+}
+
+// InitClientWithHeader initializes an HTTP client that sends authToken in the given header.
+// When hosts are given, the token is only sent to those hosts and their subdomains, so it never leaks to third party download locations.
+// Parameters:
+// - authToken: The authentication token to be used for authorized requests.
+// - header: The header the token is sent in, e.g. PrivateTokenHeader for GitLab.
+// - hosts: Optional hosts the token is scoped to.
+// Returns:
+// - An error if any issues occur during client initialization (returns nil in this implementation).
+func InitClientWithHeader(authToken string, header AuthHeader, hosts ...string) error {
 	Client = &http.Client{
 		Transport: &transportWithAuth{
 			authToken: authToken,
+			header:    header,
+			hosts:     hosts,
 			rt:        http.DefaultTransport,
 		},
 	}
@@ -43,15 +71,40 @@ func InitClient(authToken string) error {
 }
 
 // RoundTrip executes a single HTTP request using the transportWithAuth transport.
-// If an authentication token is set, it adds an "Authorization" header to the request.
+// If an authentication token is set and the request targets an allowed host, it adds the configured authentication header to the request.
 // Parameters:
 // - req: The HTTP request to be sent.
 // Returns:
 // - A pointer to the http.Response received from the server.
 // - An error if any issues occur during the request execution.
 func (t *transportWithAuth) RoundTrip(req *http.Request) (*http.Response, error) {
-	if t.authToken != consts.EMPTY_STRING {
-		req.Header.Set("Authorization", "token "+t.authToken)
+	if t.authToken != consts.EMPTY_STRING && t.allowed(req.URL.Hostname()) {
+		switch t.header {
+		case PrivateTokenHeader:
+			req.Header.Set(string(PrivateTokenHeader), t.authToken)
+		default:
+			req.Header.Set(string(AuthorizationHeader), "token "+t.authToken)
+		}
 	}
 	return t.rt.RoundTrip(req)
 }
+
+// allowed reports whether the token may be sent to host, which is the case when no hosts are configured
+// or host matches one of them or one of their subdomains.
+// Parameters:
+// - host: The host name of the request, without a port.
+// Returns: true if the token may be sent to host.
+func (t *transportWithAuth) allowed(host string) bool {
+	if len(t.hosts) == 0 {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	for _, allowed := range t.hosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
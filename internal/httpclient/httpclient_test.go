@@ -84,3 +84,74 @@ func TestRoundTrip_WithoutAuthToken(t *testing.T) {
 	require.NotNil(t, resp, "Client.Do should return a non-nil response")
 	assert.Equal(t, http.StatusOK, resp.StatusCode, "Response status code should be OK")
 }
+
+func TestRoundTrip_WithPrivateToken(t *testing.T) {
+	// Arrange
+	authToken := "test-auth-token"
+	InitClientWithHeader(authToken, PrivateTokenHeader)
+
+	// Create a test server that checks the GitLab header
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, authToken, r.Header.Get("PRIVATE-TOKEN"), "PRIVATE-TOKEN header should be set correctly")
+		assert.Empty(t, r.Header.Get("Authorization"), "Authorization header should not be set")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	// Create a request to the test server
+	req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+	require.NoError(t, err, "Failed to create request")
+
+	// Act
+	resp, err := Client.Do(req)
+
+	// Assert
+	require.NoError(t, err, "Client.Do should not return an error")
+	require.NotNil(t, resp, "Client.Do should return a non-nil response")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Response status code should be OK")
+}
+
+func TestRoundTrip_ScopedHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		hosts    []string
+		expected string
+	}{
+		{"No scope sends the token everywhere", nil, "token test-auth-token"},
+		{"Matching host", []string{"127.0.0.1"}, "token test-auth-token"},
+		{"Other host", []string{"ghe.example.com"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			InitClientWithHeader("test-auth-token", AuthorizationHeader, tt.hosts...)
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.expected, r.Header.Get("Authorization"), "Authorization header should match the host scope")
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer testServer.Close()
+
+			req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+			require.NoError(t, err, "Failed to create request")
+
+			// Act
+			resp, err := Client.Do(req)
+
+			// Assert
+			require.NoError(t, err, "Client.Do should not return an error")
+			assert.Equal(t, http.StatusOK, resp.StatusCode, "Response status code should be OK")
+		})
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	transport := &transportWithAuth{hosts: []string{"ghe.example.com", "raw.githubusercontent.com"}}
+
+	assert.True(t, transport.allowed("ghe.example.com"))
+	assert.True(t, transport.allowed("raw.ghe.example.com"))
+	assert.True(t, transport.allowed("RAW.GITHUBUSERCONTENT.COM"))
+	assert.False(t, transport.allowed("example.com"))
+	assert.False(t, transport.allowed("evilghe.example.com"))
+}
@@ -0,0 +1,122 @@
+package language
+
+import (
+	"strings"
+
+	"github-project-template/internal/consts"
+)
+
+// Language describes how a programming language is laid out in the template repository.
+// Adding support for a language is a matter of adding it to registry.
+type Language struct {
+	// Name is the canonical name of the language, e.g. python.
+	Name string
+	// Aliases are other names the language is known by, e.g. py.
+	Aliases []string
+	// ReleaseFile is the name of the release configuration file, e.g. goreleaser.yaml; empty when there is none.
+	ReleaseFile string
+	// VersionFile is the name of the version file, e.g. version.go; empty when there is none.
+	VersionFile string
+	// VersionInPackage is set when the version file lives in the package directory named after the project, e.g. my_app/__version__.py.
+	VersionInPackage bool
+	// IgnoreKey is the subdirectory of the ignore files holding the .gitignore of the language; empty is the canonical name.
+	IgnoreKey string
+	// WorkflowKey is the subdirectory of the workflow files holding the workflows of the language; empty is the canonical name.
+	WorkflowKey string
+}
+
+// registry lists every language with built-in support.
+var registry = []Language{
+	{
+		Name:        consts.GO_LANG,
+		ReleaseFile: consts.GORELEASER,
+		VersionFile: consts.VERSION_GO,
+	},
+	{
+		Name:             consts.PYTHON,
+		Aliases:          []string{consts.PY_LANG},
+		ReleaseFile:      consts.BUMPVERSION,
+		VersionFile:      consts.VERSION_PY,
+		VersionInPackage: true,
+	},
+}
+
+// Lookup finds a language by its canonical name or one of its aliases, ignoring case.
+// Parameters:
+// - name: The name of the language, e.g. py or Python.
+// Returns: The Language and whether it is known.
+func Lookup(name string) (Language, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, language := range registry {
+		if language.Name == name {
+			return language, true
+		}
+		for _, alias := range language.Aliases {
+			if alias == name {
+				return language, true
+			}
+		}
+	}
+	return Language{}, false
+}
+
+// Normalize returns the canonical name of a known language, e.g. python for py, and any other name unchanged.
+// Parameters:
+// - name: The name of the language.
+// Returns: The canonical name of the language.
+func Normalize(name string) string {
+	if language, ok := Lookup(name); ok {
+		return language.Name
+	}
+	return name
+}
+
+// Key returns the subdirectory selected for a language in the directory of a template category:
+// the ignore key for ignore files, the workflow key for workflows and the canonical name for every other category.
+// Languages that are not in the registry select the subdirectory of their name.
+// Parameters:
+// - name: The name of the language.
+// - category: The name of the template category, e.g. ignore.
+// Returns: The name of the subdirectory.
+func Key(name, category string) string {
+	language, ok := Lookup(name)
+	if !ok {
+		return name
+	}
+
+	switch {
+	case category == consts.CATEGORY_IGNORE && language.IgnoreKey != consts.EMPTY_STRING:
+		return language.IgnoreKey
+	case category == consts.CATEGORY_WORKFLOWS && language.WorkflowKey != consts.EMPTY_STRING:
+		return language.WorkflowKey
+	default:
+		return language.Name
+	}
+}
+
+// ReleaseFile returns the name of the release configuration file of a language.
+// Parameters:
+// - name: The name of the language.
+// Returns: The name of the release file, empty when the language has none or is not known.
+func ReleaseFile(name string) string {
+	language, _ := Lookup(name)
+	return language.ReleaseFile
+}
+
+// VersionFile returns the name of the version file of a language.
+// Parameters:
+// - name: The name of the language.
+// Returns: The name of the version file, empty when the language has none or is not known.
+func VersionFile(name string) string {
+	language, _ := Lookup(name)
+	return language.VersionFile
+}
+
+// VersionInPackage reports whether the version file of a language is written to the package directory named after the project.
+// Parameters:
+// - name: The name of the language.
+// Returns: true if the version file lives in the package directory, false when it lives in the project root or the language is not known.
+func VersionInPackage(name string) bool {
+	language, _ := Lookup(name)
+	return language.VersionInPackage
+}
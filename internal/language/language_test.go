@@ -0,0 +1,104 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-project-template/internal/consts"
+)
+
+// TestReleaseFile tests the ReleaseFile function
+func TestReleaseFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		projectLanguage string
+		expectedFile    string
+	}{
+		{"Empty project language", consts.EMPTY_STRING, consts.EMPTY_STRING},
+		{"Go language", consts.GO_LANG, consts.GORELEASER},
+		{"Go language (uppercase)", "GO", consts.GORELEASER},
+		{"Python language", consts.PYTHON, consts.BUMPVERSION},
+		{"Python alias", consts.PY_LANG, consts.BUMPVERSION},
+		{"Unknown language", "rust", consts.EMPTY_STRING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedFile, ReleaseFile(tt.projectLanguage))
+		})
+	}
+}
+
+// TestVersionFile tests the VersionFile function
+func TestVersionFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		projectLanguage string
+		expectedFile    string
+	}{
+		{"Empty project language", consts.EMPTY_STRING, consts.EMPTY_STRING},
+		{"Go language", consts.GO_LANG, consts.VERSION_GO},
+		{"Go language (uppercase)", "GO", consts.VERSION_GO},
+		{"Python language", consts.PYTHON, consts.VERSION_PY},
+		{"Python alias", "PY", consts.VERSION_PY},
+		{"Unknown language", "rust", consts.EMPTY_STRING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedFile, VersionFile(tt.projectLanguage))
+		})
+	}
+}
+
+// TestLookup tests finding languages by name and normalizing their names
+func TestLookup(t *testing.T) {
+	language, ok := Lookup(" Go ")
+	assert.True(t, ok)
+	assert.Equal(t, consts.GO_LANG, language.Name)
+
+	_, ok = Lookup("rust")
+	assert.False(t, ok)
+
+	assert.Equal(t, consts.GO_LANG, Normalize("GO"))
+	assert.Equal(t, consts.PYTHON, Normalize(consts.PY_LANG))
+	assert.Equal(t, consts.PYTHON, Normalize("Python"))
+	assert.Equal(t, "Rust", Normalize("Rust"))
+}
+
+// TestVersionInPackage tests which languages write their version file to the package directory
+func TestVersionInPackage(t *testing.T) {
+	assert.True(t, VersionInPackage(consts.PYTHON))
+	assert.True(t, VersionInPackage(consts.PY_LANG))
+	assert.False(t, VersionInPackage(consts.GO_LANG))
+	assert.False(t, VersionInPackage("rust"))
+}
+
+// TestKey tests the subdirectories selected for the categories of a language
+func TestKey(t *testing.T) {
+	defer func(saved []Language) { registry = saved }(registry)
+	registry = append(registry, Language{Name: "kotlin", Aliases: []string{"kt"}, IgnoreKey: "jvm", WorkflowKey: "gradle"})
+
+	tests := []struct {
+		name     string
+		language string
+		category string
+		expected string
+	}{
+		{"Canonical name", "kt", consts.CATEGORY_MAKEFILE, "kotlin"},
+		{"Ignore key", "kt", consts.CATEGORY_IGNORE, "jvm"},
+		{"Workflow key", "kotlin", consts.CATEGORY_WORKFLOWS, "gradle"},
+		{"Keys default to the name", consts.GO_LANG, consts.CATEGORY_IGNORE, consts.GO_LANG},
+		{"Python ignore files", consts.PY_LANG, consts.CATEGORY_IGNORE, consts.PYTHON},
+		{"Python workflows", consts.PY_LANG, consts.CATEGORY_WORKFLOWS, consts.PYTHON},
+		{"Python Makefile", consts.PY_LANG, consts.CATEGORY_MAKEFILE, consts.PYTHON},
+		{"Unknown language", "rust", consts.CATEGORY_IGNORE, "rust"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Key(tt.language, tt.category))
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github-project-template/internal/condition"
+	"github-project-template/internal/consts"
+	"github-project-template/internal/render"
+	"github-project-template/internal/sources"
+)
+
+// Manifest describes how a template is laid out: which directories are categories of files selected by a variable,
+// and which files are never copied by the generic walk of the template.
+type Manifest struct {
+	// Categories are the category directories of the template.
+	Categories []Category `yaml:"categories"`
+	// Skip lists file names, with or without the .tmpl suffix, that the generic walk never copies, e.g. the README of the template itself.
+	Skip []string `yaml:"skip"`
+	// Variables are the template variables the template expects, prompted for when they are not given.
+	Variables []Variable `yaml:"variables"`
+	// Files attach conditions to files and directories of the template.
+	Files []File `yaml:"files"`
+	// Hooks are the commands run before and after the template is written.
+	Hooks Hooks `yaml:"hooks"`
+}
+
+// Hooks lists the commands run around writing the template, in order.
+type Hooks struct {
+	// Pre are run in the output directory before any file is written.
+	Pre []Hook `yaml:"pre"`
+	// Post are run in the output directory after every file is written, e.g. go mod tidy.
+	Post []Hook `yaml:"post"`
+}
+
+// Hook declares a command run before or after the template is written.
+type Hook struct {
+	// Run is the shell command, rendered as a template first, e.g. go mod init {{ .module }}.
+	Run string `yaml:"run"`
+	// Dir is the working directory relative to the output directory, rendered as a template first; empty is the output directory.
+	Dir string `yaml:"dir"`
+	// Env are additional environment variables of the command, whose values are rendered as templates first.
+	Env map[string]string `yaml:"env"`
+	// When is the condition under which the command is run; empty is always.
+	When string `yaml:"when"`
+}
+
+// File attaches a condition to the files and directories of the template matching a glob pattern.
+type File struct {
+	// Path is the glob pattern matched against the slash separated paths within the template, e.g. build/Dockerfile;
+	// a pattern without a slash is matched against the names at any depth instead, e.g. *.dockerignore.
+	// Patterns match the raw names, including the .tmpl suffix and unrendered template actions.
+	Path string `yaml:"path"`
+	// When is the condition under which the matching files are written, e.g. language == "go" && include_docker.
+	When string `yaml:"when"`
+}
+
+// Variable declares a template variable expected by the template.
+type Variable struct {
+	// Name is the name of the variable, e.g. author for {{ .author }}.
+	Name string `yaml:"name"`
+	// Description explains the variable when prompting for it.
+	Description string `yaml:"description"`
+	// Type is the type of the value: string (the default), bool or int.
+	Type string `yaml:"type"`
+	// Default is used when no value is given and nobody is prompted; nil when the variable has no default.
+	Default any `yaml:"default"`
+	// Choices are the allowed values, offered as a select list when prompting.
+	Choices []string `yaml:"choices"`
+	// ChoicesFrom is a template directory whose subdirectories are the allowed values, e.g. .licenseFiles; see ResolveChoices.
+	ChoicesFrom string `yaml:"choices_from"`
+	// Required variables must have a non-empty value.
+	Required bool `yaml:"required"`
+	// Pattern is a regular expression the whole value must match, e.g. a Go module path.
+	Pattern string `yaml:"pattern"`
+	// Format is a named format the value must have; only semver is supported.
+	Format string `yaml:"format"`
+}
+
+// Category describes a directory of the template holding alternative files, one subdirectory per value of its selector variable.
+type Category struct {
+	// Name identifies the category, e.g. for --include.
+	Name string `yaml:"name"`
+	// Dir is the slash separated directory of the category within the template, e.g. .licenseFiles.
+	Dir string `yaml:"dir"`
+	// Selector is the variable whose value selects the subdirectory of Dir to read, e.g. license; empty reads Dir itself.
+	Selector string `yaml:"selector"`
+	// Files are the glob patterns of the files to write, matched against file names without the .tmpl suffix.
+	// Patterns are rendered as templates first, and empty patterns are ignored. No patterns matches every file.
+	Files []string `yaml:"files"`
+	// Dest is the directory the files are written to, relative to the output directory; empty is the output directory itself.
+	Dest string `yaml:"dest"`
+	// Prefix is prepended to the name of every written file, e.g. "." to write goreleaser.yaml as .goreleaser.yaml.
+	Prefix string `yaml:"prefix"`
+	// Optional categories are only written when included, e.g. with --include.
+	Optional bool `yaml:"optional"`
+	// When is the condition under which the category is written, e.g. include_docker; empty is always.
+	When string `yaml:"when"`
+}
+
+// Default returns the built-in manifest describing the layout of the default template repository,
+// used when a template does not ship a stub.yaml.
+// Returns: A pointer to the default Manifest.
+func Default() *Manifest {
+	return &Manifest{
+		Categories: []Category{
+			{Name: consts.CATEGORY_IGNORE, Dir: consts.IGNORE_FILES, Selector: render.LANGUAGE, Files: []string{consts.GIT_IGNORE}},
+			{Name: consts.CATEGORY_LICENSE, Dir: consts.LICENSE_FILES, Selector: render.LICENSE, Files: []string{consts.LICENSE}},
+			{Name: consts.CATEGORY_MAKEFILE, Dir: consts.MAKE_FILES, Selector: render.LANGUAGE, Files: []string{consts.MAKEFILE}, Optional: true},
+			{Name: consts.CATEGORY_README, Dir: consts.README_FILES, Selector: render.LICENSE, Files: []string{consts.README}},
+			{Name: consts.CATEGORY_TODO, Dir: consts.TODO_FILES, Selector: render.LANGUAGE, Files: []string{consts.TODO}},
+			{Name: consts.CATEGORY_RELEASE, Dir: consts.RELEASE_FILES, Selector: render.LANGUAGE, Files: []string{fmt.Sprintf("{{ .%s }}", render.RELEASE_FILE)}, Prefix: "."},
+			{Name: consts.CATEGORY_VERSION, Dir: consts.VERSION_FILES, Selector: render.LANGUAGE, Files: []string{fmt.Sprintf("{{ .%s }}", render.VERSION_FILE)}, Dest: fmt.Sprintf("{{ .%s }}", render.VERSION_DIR), Optional: true},
+			{Name: consts.CATEGORY_VSCODE, Dir: consts.VSCODE_FILES, Files: []string{"commands.json"}, Dest: consts.VSCODE},
+			{Name: consts.CATEGORY_WORKFLOWS, Dir: consts.WORKFLOW_FLIES, Selector: render.LANGUAGE, Files: []string{"*" + consts.YML}, Dest: path.Join(consts.GIT_HUB, consts.WORKFLOW)},
+		},
+		Skip: []string{consts.README, consts.LICENSE, consts.GIT_IGNORE, consts.GIT_KEEP, consts.TODO},
+	}
+}
+
+// Load reads the stub.yaml manifest at the root of the template, falling back to the default manifest when the template has none.
+// Parameters:
+// - src: The template source to read from.
+// Returns: A pointer to the Manifest and an error if the template root could not be listed or the manifest is invalid.
+func Load(src sources.TemplateSource) (*Manifest, error) {
+	items, err := src.ReadDir(consts.EMPTY_STRING)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.Type != consts.FILE_TYPE || item.Name != consts.MANIFEST {
+			continue
+		}
+
+		rc, err := src.Open(item.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %v", consts.MANIFEST, err)
+		}
+		defer rc.Close()
+
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", consts.MANIFEST, err)
+		}
+		return Parse(content)
+	}
+
+	return Default(), nil
+}
+
+// Parse decodes and validates a manifest.
+// Parameters:
+// - content: The YAML content of the manifest.
+// Returns: A pointer to the Manifest and an error if it cannot be decoded or a category or variable is invalid.
+func Parse(content []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", consts.MANIFEST, err)
+	}
+
+	names := make(map[string]bool, len(m.Categories))
+	for i, category := range m.Categories {
+		if category.Name == consts.EMPTY_STRING || category.Dir == consts.EMPTY_STRING {
+			return nil, fmt.Errorf("invalid %s: category %d needs a name and a dir", consts.MANIFEST, i+1)
+		}
+		if names[category.Name] {
+			return nil, fmt.Errorf("invalid %s: duplicate category %s", consts.MANIFEST, category.Name)
+		}
+		if _, err := condition.Parse(category.When); err != nil {
+			return nil, fmt.Errorf("invalid %s: category %s: %v", consts.MANIFEST, category.Name, err)
+		}
+		names[category.Name] = true
+	}
+
+	for i, file := range m.Files {
+		if file.Path == consts.EMPTY_STRING {
+			return nil, fmt.Errorf("invalid %s: file %d needs a path", consts.MANIFEST, i+1)
+		}
+		if _, err := path.Match(file.Path, consts.EMPTY_STRING); err != nil {
+			return nil, fmt.Errorf("invalid %s: file %s is not a valid pattern: %v", consts.MANIFEST, file.Path, err)
+		}
+		if _, err := condition.Parse(file.When); err != nil {
+			return nil, fmt.Errorf("invalid %s: file %s: %v", consts.MANIFEST, file.Path, err)
+		}
+	}
+
+	variables := make(map[string]bool, len(m.Variables))
+	for i, variable := range m.Variables {
+		if variable.Name == consts.EMPTY_STRING {
+			return nil, fmt.Errorf("invalid %s: variable %d needs a name", consts.MANIFEST, i+1)
+		}
+		if variables[variable.Name] {
+			return nil, fmt.Errorf("invalid %s: duplicate variable %s", consts.MANIFEST, variable.Name)
+		}
+		switch variable.Type {
+		case consts.EMPTY_STRING, consts.VAR_TYPE_STRING, consts.VAR_TYPE_BOOL, consts.VAR_TYPE_INT:
+		default:
+			return nil, fmt.Errorf("invalid %s: variable %s has unknown type %s", consts.MANIFEST, variable.Name, variable.Type)
+		}
+		if _, err := regexp.Compile(variable.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid %s: variable %s has an invalid pattern: %v", consts.MANIFEST, variable.Name, err)
+		}
+		if variable.Format != consts.EMPTY_STRING && variable.Format != consts.VAR_FORMAT_SEMVER {
+			return nil, fmt.Errorf("invalid %s: variable %s has unknown format %s", consts.MANIFEST, variable.Name, variable.Format)
+		}
+		variables[variable.Name] = true
+	}
+
+	if err := validateHooks(consts.HOOK_PRE, m.Hooks.Pre); err != nil {
+		return nil, err
+	}
+	if err := validateHooks(consts.HOOK_POST, m.Hooks.Post); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// validateHooks checks that every hook of a stage has a command and a valid condition.
+// Parameters:
+// - stage: The stage of the hooks, pre or post.
+// - hooks: The hooks of the stage.
+// Returns: An error describing the first invalid hook.
+func validateHooks(stage string, hooks []Hook) error {
+	for i, hook := range hooks {
+		if strings.TrimSpace(hook.Run) == consts.EMPTY_STRING {
+			return fmt.Errorf("invalid %s: %s hook %d needs a command to run", consts.MANIFEST, stage, i+1)
+		}
+		if _, err := condition.Parse(hook.When); err != nil {
+			return fmt.Errorf("invalid %s: %s hook %d: %v", consts.MANIFEST, stage, i+1, err)
+		}
+	}
+	return nil
+}
+
+// ResolveChoices sets the choices of every variable declaring choices_from to the names of the subdirectories of that template directory,
+// so e.g. license can only be one of the licenses the template provides.
+// Parameters:
+// - src: The template source to read from.
+// Returns: An error if a directory could not be listed.
+func (m *Manifest) ResolveChoices(src sources.TemplateSource) error {
+	for i := range m.Variables {
+		variable := &m.Variables[i]
+		if variable.ChoicesFrom == consts.EMPTY_STRING {
+			continue
+		}
+
+		items, err := src.ReadDir(cleanDir(variable.ChoicesFrom))
+		if err != nil {
+			return fmt.Errorf("failed to list choices of %s from %s: %v", variable.Name, variable.ChoicesFrom, err)
+		}
+
+		variable.Choices = nil
+		for _, item := range items {
+			if item.Type == consts.DIR_TYPE {
+				variable.Choices = append(variable.Choices, item.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// cleanDir normalizes a slash separated template directory, where the template root is empty.
+func cleanDir(dir string) string {
+	return strings.Trim(path.Clean("/"+dir), "/")
+}
+
+// Category returns the category whose directory is dir.
+// A category directory without a slash, such as .ignoreFiles, matches directories of that name at any depth of the template;
+// any other category directory, such as templates/licenses, matches the full path.
+// Parameters:
+// - dir: The slash separated directory within the template.
+// Returns: A pointer to the Category, or nil when dir is not a category directory.
+func (m *Manifest) Category(dir string) *Category {
+	for i := range m.Categories {
+		if m.Categories[i].matches(dir) {
+			return &m.Categories[i]
+		}
+	}
+	return nil
+}
+
+// matches reports whether the directory of the category is the template directory, or its name when the directory has no slash.
+func (c Category) matches(dir string) bool {
+	target := dir
+	if !strings.Contains(cleanDir(c.Dir), "/") {
+		target = path.Base(dir)
+	}
+	return target == cleanDir(c.Dir)
+}
+
+// Skips reports whether the generic walk of the template leaves out the file with the given name,
+// which applies to the names listed in Skip and to the manifest itself.
+// Parameters:
+// - name: The name of the file without the .tmpl suffix.
+// Returns: true if the file is not copied.
+func (m *Manifest) Skips(name string) bool {
+	if name == consts.MANIFEST {
+		return true
+	}
+	for _, skip := range m.Skip {
+		if skip == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the file or directory at the given template path is written, i.e. the conditions of every file pattern matching it hold.
+// Parameters:
+// - p: The slash separated path within the template.
+// - data: The values the conditions are evaluated with.
+// Returns: true if the file or directory is written and an error if a condition could not be evaluated.
+func (m *Manifest) Allows(p string, data map[string]any) (bool, error) {
+	for _, file := range m.Files {
+		if !file.matches(p) {
+			continue
+		}
+
+		ok, err := condition.Eval(file.When, data)
+		if err != nil {
+			return false, fmt.Errorf("file %s: %v", file.Path, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matches reports whether the pattern of the file matches the template path, or its name when the pattern has no slash.
+func (f File) matches(p string) bool {
+	target := p
+	if !strings.Contains(f.Path, "/") {
+		target = path.Base(p)
+	}
+	ok, _ := path.Match(f.Path, target)
+	return ok
+}
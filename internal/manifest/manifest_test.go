@@ -0,0 +1,185 @@
+package manifest
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/sources"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    *Manifest
+		expectedErr bool
+	}{
+		{
+			name: "Categories",
+			content: `
+categories:
+  - name: docker
+    dir: .dockerFiles
+    selector: language
+    files: [Dockerfile, "*.dockerignore"]
+    dest: build
+    optional: true
+skip: [README.md]
+`,
+			expected: &Manifest{
+				Categories: []Category{{Name: "docker", Dir: ".dockerFiles", Selector: "language", Files: []string{"Dockerfile", "*.dockerignore"}, Dest: "build", Optional: true}},
+				Skip:       []string{"README.md"},
+			},
+		},
+		{
+			name:     "Variables",
+			content:  "variables:\n  - {name: author, description: Name of the author}\n  - {name: docker, type: bool, default: false}\n  - {name: license, choices: [mit, apache]}\n",
+			expected: &Manifest{Variables: []Variable{{Name: "author", Description: "Name of the author"}, {Name: "docker", Type: "bool", Default: false}, {Name: "license", Choices: []string{"mit", "apache"}}}},
+		},
+		{name: "Unnamed variable", content: "variables:\n  - {description: x}\n", expectedErr: true},
+		{name: "Duplicate variable", content: "variables:\n  - {name: a}\n  - {name: a}\n", expectedErr: true},
+		{
+			name:     "Variable schema",
+			content:  "variables:\n  - {name: module, required: true, pattern: '[a-z.]+/.+'}\n  - {name: go_version, format: semver}\n  - {name: license, choices_from: .licenseFiles}\n",
+			expected: &Manifest{Variables: []Variable{{Name: "module", Required: true, Pattern: "[a-z.]+/.+"}, {Name: "go_version", Format: "semver"}, {Name: "license", ChoicesFrom: ".licenseFiles"}}},
+		},
+		{
+			name:     "Conditions",
+			content:  "categories:\n  - {name: docker, dir: .dockerFiles, when: .include_docker}\nfiles:\n  - {path: build/Dockerfile, when: 'eq .language \"go\"'}\n  - {path: docs, when: 'language == \"go\" && include_docker'}\n",
+			expected: &Manifest{Categories: []Category{{Name: "docker", Dir: ".dockerFiles", When: ".include_docker"}}, Files: []File{{Path: "build/Dockerfile", When: `eq .language "go"`}, {Path: "docs", When: `language == "go" && include_docker`}}},
+		},
+		{name: "Invalid category condition", content: "categories:\n  - {name: a, dir: .a, when: '(eq .a'}\n", expectedErr: true},
+		{name: "Invalid file condition", content: "files:\n  - {path: a, when: 'unknown .a'}\n", expectedErr: true},
+		{name: "Invalid operator condition", content: "files:\n  - {path: a, when: 'a == '}\n", expectedErr: true},
+		{name: "File without path", content: "files:\n  - {when: .a}\n", expectedErr: true},
+		{name: "Invalid file pattern", content: "files:\n  - {path: '[', when: .a}\n", expectedErr: true},
+		{
+			name:     "Hooks",
+			content:  "hooks:\n  post:\n    - {run: go mod tidy, dir: app, env: {GOFLAGS: -mod=mod}, when: 'eq .language \"go\"'}\n",
+			expected: &Manifest{Hooks: Hooks{Post: []Hook{{Run: "go mod tidy", Dir: "app", Env: map[string]string{"GOFLAGS": "-mod=mod"}, When: `eq .language "go"`}}}},
+		},
+		{name: "Hook without command", content: "hooks:\n  pre:\n    - {dir: app}\n", expectedErr: true},
+		{name: "Invalid hook condition", content: "hooks:\n  post:\n    - {run: ls, when: '{{'}\n", expectedErr: true},
+		{name: "Unknown variable type", content: "variables:\n  - {name: a, type: float}\n", expectedErr: true},
+		{name: "Invalid variable pattern", content: "variables:\n  - {name: a, pattern: '('}\n", expectedErr: true},
+		{name: "Unknown variable format", content: "variables:\n  - {name: a, format: email}\n", expectedErr: true},
+		{name: "Missing dir", content: "categories:\n  - name: docker\n", expectedErr: true},
+		{name: "Duplicate name", content: "categories:\n  - {name: a, dir: .a}\n  - {name: a, dir: .b}\n", expectedErr: true},
+		{name: "Invalid yaml", content: "categories: [", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.content))
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	src, err := sources.NewLocalSource(dir)
+	require.NoError(t, err)
+
+	m, err := Load(src)
+	require.NoError(t, err)
+	assert.Equal(t, Default(), m)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, consts.MANIFEST), []byte("categories:\n  - {name: docker, dir: .dockerFiles}\n"), 0644))
+	m, err = Load(src)
+	require.NoError(t, err)
+	assert.Equal(t, []Category{{Name: "docker", Dir: ".dockerFiles"}}, m.Categories)
+}
+
+func TestManifestLookups(t *testing.T) {
+	m := Default()
+
+	category := m.Category(consts.LICENSE_FILES)
+	require.NotNil(t, category)
+	assert.Equal(t, consts.CATEGORY_LICENSE, category.Name)
+	assert.Nil(t, m.Category("docs"))
+
+	// directory names match at any depth, paths only in full
+	category = m.Category(path.Join("go-cli", consts.LICENSE_FILES))
+	require.NotNil(t, category)
+	assert.Equal(t, consts.CATEGORY_LICENSE, category.Name)
+	nested := &Manifest{Categories: []Category{{Name: "licenses", Dir: "templates/licenses"}}}
+	assert.NotNil(t, nested.Category("templates/licenses"))
+	assert.Nil(t, nested.Category("licenses"))
+	assert.Nil(t, nested.Category("other/templates/licenses"))
+
+	assert.True(t, m.Skips(consts.README))
+	assert.True(t, m.Skips(consts.MANIFEST))
+	assert.False(t, m.Skips("main.go"))
+}
+
+func TestResolveChoices(t *testing.T) {
+	dir := t.TempDir()
+	for _, license := range []string{"apache", "mit"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, consts.LICENSE_FILES, license), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, consts.LICENSE_FILES, consts.README), []byte("licenses"), 0644))
+	src, err := sources.NewLocalSource(dir)
+	require.NoError(t, err)
+
+	m := &Manifest{Variables: []Variable{{Name: "license", ChoicesFrom: consts.LICENSE_FILES}, {Name: "author"}}}
+	require.NoError(t, m.ResolveChoices(src))
+	assert.Equal(t, []string{"apache", "mit"}, m.Variables[0].Choices)
+	assert.Nil(t, m.Variables[1].Choices)
+
+	m = &Manifest{Variables: []Variable{{Name: "license", ChoicesFrom: ".missing"}}}
+	assert.Error(t, m.ResolveChoices(src))
+}
+
+func TestAllows(t *testing.T) {
+	m := &Manifest{Files: []File{
+		{Path: "build/Dockerfile", When: ".include_docker"},
+		{Path: "*.dockerignore", When: ".include_docker"},
+		{Path: "docs", When: `eq .language "go"`},
+		{Path: "unset", When: ".missing"},
+		{Path: "compose.yaml", When: `language == "go" && include_docker`},
+		{Path: "go.mod", When: `language == "go" && !include_docker`},
+		{Path: "broken", When: `lt .port "a"`},
+	}}
+	data := map[string]any{"include_docker": "false", "language": "go", "port": 8080}
+
+	tests := []struct {
+		name        string
+		path        string
+		expected    bool
+		expectedErr bool
+	}{
+		{name: "Unmatched", path: "main.go", expected: true},
+		{name: "Path", path: "build/Dockerfile", expected: false},
+		{name: "Name at any depth", path: "build/app/.dockerignore", expected: false},
+		{name: "Directory", path: "docs", expected: true},
+		{name: "Path does not match name", path: "other/build/Dockerfile", expected: true},
+		{name: "Unset variable", path: "unset", expected: false},
+		{name: "Operators", path: "app/compose.yaml", expected: false},
+		{name: "Negated false string", path: "go.mod", expected: true},
+		{name: "Invalid comparison", path: "broken", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := m.Allows(tt.path, data)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, allowed)
+		})
+	}
+}
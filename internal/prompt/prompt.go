@@ -0,0 +1,70 @@
+package prompt
+
+import (
+	"os"
+
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+
+	"github-project-template/internal/consts"
+)
+
+// Prompter asks the user for values.
+type Prompter interface {
+	// Select asks the user to pick one of choices, preselecting def when it is one of them.
+	Select(message string, choices []string, def string) (string, error)
+	// Input asks the user to type a value, using def when the answer is empty.
+	Input(message, def string) (string, error)
+	// Confirm asks the user a yes/no question.
+	Confirm(message string, def bool) (bool, error)
+}
+
+// Terminal is a Prompter drawing interactive prompts on the terminal.
+type Terminal struct{}
+
+// IsInteractive reports whether both standard input and standard output are terminals, so the user can be prompted.
+// Returns: true if prompts can be shown.
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Select shows a select list of choices.
+// Parameters:
+// - message: The question to show.
+// - choices: The values to pick from.
+// - def: The preselected value.
+// Returns: The picked value and an error if the prompt fails.
+func (Terminal) Select(message string, choices []string, def string) (string, error) {
+	printer := pterm.DefaultInteractiveSelect.WithOptions(choices)
+	for _, choice := range choices {
+		if choice == def {
+			printer = printer.WithDefaultOption(def)
+		}
+	}
+	return printer.Show(message)
+}
+
+// Input shows a text input.
+// Parameters:
+// - message: The question to show.
+// - def: The value used when the answer is empty.
+// Returns: The answer and an error if the prompt fails.
+func (Terminal) Input(message, def string) (string, error) {
+	answer, err := pterm.DefaultInteractiveTextInput.WithDefaultValue(def).Show(message)
+	if err != nil {
+		return answer, err
+	}
+	if answer == consts.EMPTY_STRING {
+		answer = def
+	}
+	return answer, nil
+}
+
+// Confirm shows a yes/no question.
+// Parameters:
+// - message: The question to show.
+// - def: The answer used when the user just presses enter.
+// Returns: The answer and an error if the prompt fails.
+func (Terminal) Confirm(message string, def bool) (bool, error) {
+	return pterm.DefaultInteractiveConfirm.WithDefaultValue(def).Show(message)
+}
@@ -0,0 +1,85 @@
+package render
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github-project-template/internal/consts"
+)
+
+var (
+	// majorVersionPattern matches the major version suffix of a Go module path, e.g. /v2.
+	majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+	// binaryNameInvalid matches runs of characters that are not allowed in binary names.
+	binaryNameInvalid = regexp.MustCompile(`[^a-z0-9._-]+`)
+	// packageNameInvalid matches runs of characters that are not allowed in package names.
+	packageNameInvalid = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// FuncMap returns the functions available to templates: the Sprig function library
+// (e.g. snakecase, kebabcase, camelcase, upper, default, now | date, trimSuffix) plus project specific helpers.
+// Returns: The template.FuncMap used when rendering templates.
+func FuncMap() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	funcs["goModulePath"] = goModulePath
+	funcs["binaryName"] = binaryName
+	funcs["packageName"] = packageName
+	funcs["gitConfig"] = gitConfig
+	return funcs
+}
+
+// goModulePath builds the Go module path of a project hosted on GitHub, e.g. {{ goModulePath .owner .project_name }}.
+// Parameters:
+// - owner: The owner of the repository.
+// - name: The name of the repository.
+// Returns: The module path, github.com/<owner>/<name>.
+func goModulePath(owner, name string) string {
+	return fmt.Sprintf("github.com/%s/%s", owner, name)
+}
+
+// binaryName derives the name of the executable built from a project or module path, e.g. {{ binaryName .module }}.
+// The last element of the path is used, skipping a Go major version suffix such as /v2, lowercased,
+// and every run of characters other than letters, digits, dots, underscores and dashes is replaced with a dash.
+// Parameters:
+// - name: The project name or module path.
+// Returns: The binary name, e.g. my-app for github.com/acme/My App/v2.
+func binaryName(name string) string {
+	name = strings.Trim(name, "/")
+	if base := path.Base(name); majorVersionPattern.MatchString(base) && path.Dir(name) != "." {
+		name = path.Dir(name)
+	}
+
+	return strings.Trim(binaryNameInvalid.ReplaceAllString(strings.ToLower(path.Base(name)), "-"), "-")
+}
+
+// packageName derives the name of the package of a project that can be imported, e.g. {{ packageName .project_name }} for a Python package.
+// The name is lowercased, every run of characters other than letters, digits and underscores is replaced with an underscore,
+// and a name starting with a digit is prefixed with an underscore.
+// Parameters:
+// - name: The project name.
+// Returns: The package name, e.g. my_app for My-App.
+func packageName(name string) string {
+	name = strings.Trim(packageNameInvalid.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name != consts.EMPTY_STRING && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// gitConfig reads a value of the user's git configuration, e.g. {{ gitConfig "user.name" }} as the default author.
+// Parameters:
+// - key: The git configuration key.
+// Returns: The configured value, or an empty string when git is not installed or the key is not set.
+func gitConfig(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return consts.EMPTY_STRING
+	}
+	return strings.TrimSpace(string(out))
+}
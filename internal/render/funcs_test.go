@@ -0,0 +1,95 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Project name", "my-app", "my-app"},
+		{"Module path", "github.com/acme/tool", "tool"},
+		{"Major version suffix", "github.com/acme/tool/v2", "tool"},
+		{"Mixed case and spaces", "My Cool App", "my-cool-app"},
+		{"Version only", "v2", "v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, binaryName(tt.input))
+		})
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Project name", "my-app", "my_app"},
+		{"Already a package name", "my_app", "my_app"},
+		{"Mixed case, dots and spaces", "My.Cool App", "my_cool_app"},
+		{"Leading digit", "2fa-service", "_2fa_service"},
+		{"Surrounding separators", "-app-", "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, packageName(tt.input))
+		})
+	}
+}
+
+func TestRenderFuncs(t *testing.T) {
+	data := Data{PROJECT_NAME: "myCoolApp", OWNER: "acme"}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"Snake case", "{{ snakecase .project_name }}", "my_cool_app"},
+		{"Kebab case", "{{ kebabcase .project_name }}", "my-cool-app"},
+		{"Upper", "{{ upper .owner }}", "ACME"},
+		{"Default", `{{ default "MIT" "" }}`, "MIT"},
+		{"Trim suffix", `{{ trimSuffix "-app" "cool-app" }}`, "cool"},
+		{"Module path", "{{ goModulePath .owner .project_name }}", "github.com/acme/myCoolApp"},
+		{"Binary name", "{{ goModulePath .owner .project_name | binaryName }}", "mycoolapp"},
+		{"Package name", "{{ snakecase .project_name | packageName }}", "my_cool_app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Render(tt.name, []byte(tt.content), data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(rendered))
+		})
+	}
+}
+
+func TestGitConfig(t *testing.T) {
+	// run outside of any repository, so only the global configuration is read
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	config := filepath.Join(dir, "gitconfig")
+	require.NoError(t, os.WriteFile(config, []byte("[user]\n\tname = Jane Doe\n"), 0644))
+	t.Setenv("GIT_CONFIG_GLOBAL", config)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	assert.Equal(t, "Jane Doe", gitConfig("user.name"))
+	assert.Equal(t, "", gitConfig("user.missing"))
+}
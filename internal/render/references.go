@@ -0,0 +1,79 @@
+package render
+
+import (
+	"fmt"
+	"text/template"
+	"text/template/parse"
+)
+
+// References lists the top-level values a template refers to, e.g. owner and project_name for github.com/{{ .owner }}/{{ .project_name }}.
+// Parameters:
+// - text: The template text.
+// Returns: The names of the referenced values in order of first use and an error if the template could not be parsed.
+func References(text string) ([]string, error) {
+	tmpl, err := template.New("references").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %q: %v", text, err)
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectReferences(t.Tree.Root, seen, &names)
+		}
+	}
+	return names, nil
+}
+
+// collectReferences walks a template parse tree and appends the first identifier of every field, e.g. owner for .owner.name, to names.
+// Parameters:
+// - node: The node to walk.
+// - seen: The names collected so far.
+// - names: The collected names, in order of first use.
+func collectReferences(node parse.Node, seen map[string]bool, names *[]string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectReferences(child, seen, names)
+		}
+	case *parse.ActionNode:
+		collectReferences(n.Pipe, seen, names)
+	case *parse.IfNode:
+		collectBranchReferences(&n.BranchNode, seen, names)
+	case *parse.RangeNode:
+		collectBranchReferences(&n.BranchNode, seen, names)
+	case *parse.WithNode:
+		collectBranchReferences(&n.BranchNode, seen, names)
+	case *parse.TemplateNode:
+		collectReferences(n.Pipe, seen, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectReferences(cmd, seen, names)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectReferences(arg, seen, names)
+		}
+	case *parse.ChainNode:
+		collectReferences(n.Node, seen, names)
+	case *parse.FieldNode:
+		if name := n.Ident[0]; !seen[name] {
+			seen[name] = true
+			*names = append(*names, name)
+		}
+	}
+}
+
+// collectBranchReferences walks the pipeline and both branches of an if, range or with node.
+func collectBranchReferences(n *parse.BranchNode, seen map[string]bool, names *[]string) {
+	collectReferences(n.Pipe, seen, names)
+	collectReferences(n.List, seen, names)
+	collectReferences(n.ElseList, seen, names)
+}
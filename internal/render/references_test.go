@@ -0,0 +1,33 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"Plain text", "github.com", nil},
+		{"Fields", "github.com/{{ .owner }}/{{ .project_name }}", []string{"owner", "project_name"}},
+		{"Functions and pipes", "{{ .project_name | kebabcase }}-{{ goModulePath .owner .project_name }}", []string{"project_name", "owner"}},
+		{"Branches", `{{ if eq .language "go" }}{{ .module }}{{ else }}{{ .owner.name }}{{ end }}`, []string{"language", "module", "owner"}},
+		{"Range and with", "{{ range .items }}{{ . }}{{ end }}{{ with .license }}{{ . }}{{ end }}", []string{"items", "license"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			references, err := References(tt.text)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, references)
+		})
+	}
+
+	_, err := References("{{ .owner ")
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/language"
+	"github-project-template/internal/types"
+	"github-project-template/internal/utils"
+)
+
+// Keys of the built-in values available to templates, e.g. {{ .project_name }}.
+const (
+	// PROJECT_NAME is the name of the project, derived from the output directory.
+	PROJECT_NAME = "project_name"
+	// OWNER is the owner of the project; only set when it is given, never from the owner of the default template repository.
+	OWNER = "owner"
+	// YEAR is the current year.
+	YEAR = "year"
+	// LANGUAGE is the programming language of the project.
+	LANGUAGE = "language"
+	// LICENSE is the license type of the project.
+	LICENSE = "license"
+	// MODULE is the module path of the project, e.g. github.com/owner/project; only set by default when the owner is set.
+	MODULE = "module"
+	// RELEASE_FILE is the name of the release configuration file of the language, e.g. goreleaser.yaml; empty when there is none.
+	RELEASE_FILE = "release_file"
+	// VERSION_FILE is the name of the version file of the language, e.g. version.go; empty when there is none.
+	VERSION_FILE = "version_file"
+	// VERSION_DIR is the directory the version file is written to, e.g. the package directory my_app of a Python project; empty is the project root.
+	VERSION_DIR = "version_dir"
+)
+
+// Data holds the values available to templates, keyed by name.
+type Data map[string]any
+
+// NewData builds the template data model from the CLI options.
+// The built-in values are overridden by the template variables in opts.Vars, and the resulting language is normalized to its canonical name, e.g. python for py.
+// The owner is only set when it is a variable, i.e. given with --repo-owner, --var, the environment, the config file or the template reference,
+// so templates referring to it fail instead of naming the owner of the default template repository.
+// Unless they are set explicitly, the release and version file names and the version directory come from the language registry, and the module path is derived from the owner and project name.
+// Parameters:
+// - opts: CLI options of type types.CliFlags, including the output directory, project language, license type and variables.
+// Returns: The Data holding the project name, owner, year, language, license type, module path, release and version file names, version directory and every template variable.
+func NewData(opts types.CliFlags) Data {
+	projectName := filepath.Base(opts.OutputDirectory)
+	if abs, err := filepath.Abs(opts.OutputDirectory); err == nil {
+		projectName = filepath.Base(abs)
+	}
+
+	data := Data{
+		PROJECT_NAME: projectName,
+		YEAR:         time.Now().Year(),
+		LANGUAGE:     opts.ProjectLanguage,
+		LICENSE:      opts.LicenseType,
+	}
+	for key, variable := range opts.Vars {
+		data[key] = variable.Value
+	}
+	data[LANGUAGE] = language.Normalize(fmt.Sprint(data[LANGUAGE]))
+	// languages without a release or version file leave them empty, which selects no file
+	if _, ok := data[RELEASE_FILE]; !ok {
+		data[RELEASE_FILE] = language.ReleaseFile(fmt.Sprint(data[LANGUAGE]))
+	}
+	if _, ok := data[VERSION_FILE]; !ok {
+		data[VERSION_FILE] = language.VersionFile(fmt.Sprint(data[LANGUAGE]))
+	}
+	if _, ok := data[VERSION_DIR]; !ok {
+		data[VERSION_DIR] = consts.EMPTY_STRING
+		if language.VersionInPackage(fmt.Sprint(data[LANGUAGE])) {
+			data[VERSION_DIR] = packageName(fmt.Sprint(data[PROJECT_NAME]))
+		}
+	}
+	if _, ok := data[MODULE]; !ok {
+		if owner, ok := data[OWNER]; ok {
+			data[MODULE] = goModulePath(fmt.Sprint(owner), fmt.Sprint(data[PROJECT_NAME]))
+		}
+	}
+
+	return data
+}
+
+// IsTemplate reports whether the file at path opts in to rendering, i.e. carries the .tmpl suffix.
+// Parameters:
+// - path: The path of the template file.
+// Returns: true if the file is rendered before it is written.
+func IsTemplate(path string) bool {
+	return strings.HasSuffix(path, consts.TMPL)
+}
+
+// TargetPath returns the path a template file is written to, with the .tmpl suffix stripped.
+// Parameters:
+// - path: The path of the template file.
+// Returns: The path without the .tmpl suffix.
+func TargetPath(path string) string {
+	return strings.TrimSuffix(path, consts.TMPL)
+}
+
+// Path renders the slash separated path of a template file, e.g. cmd/{{ .binary_name }}/main.go.
+// Every element containing a template action is rendered on its own, and the path is empty when any element renders empty,
+// so a file or the files of a directory can be left out with e.g. {{ if .docker }}Dockerfile{{ end }}.
+// Parameters:
+// - p: The slash separated path within the template.
+// - data: The values available to the template.
+// Returns: The rendered path, empty when the file is left out, and an error if an element could not be rendered or the path leaves the output directory.
+func Path(p string, data Data) (string, error) {
+	if !strings.Contains(p, "{{") {
+		return p, nil
+	}
+
+	elements := strings.Split(p, "/")
+	for i, element := range elements {
+		if !strings.Contains(element, "{{") {
+			continue
+		}
+
+		rendered, err := Render(p, []byte(element), data)
+		if err != nil {
+			return consts.EMPTY_STRING, err
+		}
+		if strings.TrimSpace(string(rendered)) == consts.EMPTY_STRING {
+			return consts.EMPTY_STRING, nil
+		}
+		elements[i] = string(rendered)
+	}
+
+	rendered := path.Clean(strings.Join(elements, "/"))
+	if rendered == ".." || strings.HasPrefix(rendered, "../") || path.IsAbs(rendered) {
+		return consts.EMPTY_STRING, fmt.Errorf("template path %s renders to %s, outside of the output directory", p, rendered)
+	}
+	return rendered, nil
+}
+
+// Render executes content as a text/template with the given data and the functions of FuncMap.
+// Referencing a value that is not in data is an error, so typos in templates are not silently rendered as <no value>.
+// Parameters:
+// - name: The name of the template, used in error messages.
+// - content: The template text.
+// - data: The values available to the template.
+// Returns: The rendered content and an error if the template could not be parsed or executed.
+func Render(name string, content []byte, data Data) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(FuncMap()).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %v", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Open wraps open so the contents it returns are rendered with the given data.
+// Parameters:
+// - open: The function opening the raw template contents.
+// - name: The name of the template, used in error messages.
+// - data: The values available to the template.
+// Returns: An OpenFunc returning the rendered contents.
+func Open(open utils.OpenFunc, name string, data Data) utils.OpenFunc {
+	return func() (io.ReadCloser, error) {
+		in, err := open()
+		if err != nil {
+			return nil, err
+		}
+		defer in.Close()
+
+		content, err := io.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %v", name, err)
+		}
+
+		rendered, err := Render(name, content, data)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(rendered)), nil
+	}
+}
@@ -0,0 +1,193 @@
+package render
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+func TestNewData(t *testing.T) {
+	data := NewData(types.CliFlags{
+		OutputDirectory: filepath.Join("projects", "my-app"),
+		RepoOwner:       "acme",
+		ProjectLanguage: "go",
+		LicenseType:     "mit",
+	})
+
+	// the owner of the template repository is not the owner of the project
+	assert.Equal(t, Data{
+		PROJECT_NAME: "my-app",
+		YEAR:         time.Now().Year(),
+		LANGUAGE:     "go",
+		LICENSE:      "mit",
+		RELEASE_FILE: "goreleaser.yaml",
+		VERSION_FILE: "version.go",
+		VERSION_DIR:  "",
+	}, data)
+}
+
+func TestNewDataVars(t *testing.T) {
+	data := NewData(types.CliFlags{
+		OutputDirectory: "my-app",
+		RepoOwner:       "ondrovic",
+		Vars: map[string]types.Variable{
+			OWNER:    {Value: "acme", Source: consts.VAR_SOURCE_FLAG},
+			"author": {Value: "Jane Doe", Source: consts.VAR_SOURCE_ENV},
+		},
+	})
+
+	assert.Equal(t, "acme", data[OWNER])
+	assert.Equal(t, "Jane Doe", data["author"])
+	assert.Equal(t, "github.com/acme/my-app", data[MODULE])
+
+	data = NewData(types.CliFlags{
+		OutputDirectory: "my-app",
+		Vars:            map[string]types.Variable{MODULE: {Value: "example.com/foo", Source: consts.VAR_SOURCE_FILE}},
+	})
+	assert.Equal(t, "example.com/foo", data[MODULE])
+
+	data = NewData(types.CliFlags{
+		OutputDirectory: "my-app",
+		RepoOwner:       "ondrovic",
+		Vars:            map[string]types.Variable{OWNER: {Value: "acme", Source: consts.VAR_SOURCE_TEMPLATE}},
+	})
+	assert.Equal(t, "github.com/acme/my-app", data[MODULE])
+
+	// the owner of the default template repository is neither the owner nor part of the module path
+	data = NewData(types.CliFlags{OutputDirectory: "my-app", RepoOwner: "ondrovic"})
+	assert.NotContains(t, data, OWNER)
+	assert.NotContains(t, data, MODULE)
+	_, err := Render("LICENSE.tmpl", []byte("MIT {{ .year }} {{ .owner }}"), data)
+	assert.Error(t, err)
+
+	data = NewData(types.CliFlags{
+		OutputDirectory: "my-app",
+		ProjectLanguage: "GO",
+	})
+	assert.Equal(t, "go", data[LANGUAGE])
+	assert.Equal(t, "goreleaser.yaml", data[RELEASE_FILE])
+
+	data = NewData(types.CliFlags{
+		OutputDirectory: "my-svc",
+		ProjectLanguage: "py",
+	})
+	assert.Equal(t, "python", data[LANGUAGE])
+	assert.Equal(t, "bumpversion.cfg", data[RELEASE_FILE])
+	assert.Equal(t, "__version__.py", data[VERSION_FILE])
+	assert.Equal(t, "my_svc", data[VERSION_DIR])
+
+	// the version directory follows the project name and can be set explicitly
+	data = NewData(types.CliFlags{
+		OutputDirectory: "svc",
+		ProjectLanguage: "python",
+		Vars:            map[string]types.Variable{PROJECT_NAME: {Value: "My Service", Source: consts.VAR_SOURCE_FLAG}},
+	})
+	assert.Equal(t, "my_service", data[VERSION_DIR])
+	data = NewData(types.CliFlags{
+		OutputDirectory: "svc",
+		ProjectLanguage: "python",
+		Vars:            map[string]types.Variable{VERSION_DIR: {Value: "src/svc", Source: consts.VAR_SOURCE_FLAG}},
+	})
+	assert.Equal(t, "src/svc", data[VERSION_DIR])
+}
+
+func TestTemplatePaths(t *testing.T) {
+	tests := []struct {
+		path           string
+		expectedIsTmpl bool
+		expectedTarget string
+	}{
+		{"README.md.tmpl", true, "README.md"},
+		{".licenseFiles/mit/LICENSE.tmpl", true, ".licenseFiles/mit/LICENSE"},
+		{"logo.png", false, "logo.png"},
+		{"notes.tmpl.md", false, "notes.tmpl.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expectedIsTmpl, IsTemplate(tt.path))
+			assert.Equal(t, tt.expectedTarget, TargetPath(tt.path))
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	data := Data{"binary_name": "tool", "docker": false, "dir": "../.."}
+
+	tests := []struct {
+		name        string
+		path        string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "Plain", path: "cmd/main.go", expected: "cmd/main.go"},
+		{name: "Directory", path: "cmd/{{ .binary_name }}/main.go", expected: "cmd/tool/main.go"},
+		{name: "File name", path: "{{ .binary_name }}.yaml.tmpl", expected: "tool.yaml.tmpl"},
+		{name: "Empty file name", path: "build/{{ if .docker }}Dockerfile{{ end }}", expected: consts.EMPTY_STRING},
+		{name: "Empty directory", path: "{{ if .docker }}docker{{ end }}/compose.yaml", expected: consts.EMPTY_STRING},
+		{name: "Missing key", path: "{{ .missing }}/main.go", expectedErr: true},
+		{name: "Outside of the output directory", path: "{{ .dir }}/main.go", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Path(tt.path, data)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rendered)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	data := Data{PROJECT_NAME: "my-app", YEAR: 2024, OWNER: "acme"}
+
+	tests := []struct {
+		name        string
+		content     string
+		expected    string
+		expectedErr bool
+	}{
+		{"Values", "Copyright (c) {{ .year }} {{ .owner }}", "Copyright (c) 2024 acme", false},
+		{"Literal text", "no actions here", "no actions here", false},
+		{"Missing value", "{{ .author }}", "", true},
+		{"Parse error", "{{ .project_name ", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Render("LICENSE.tmpl", []byte(tt.content), data)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(rendered))
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	open := Open(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte("# {{ .project_name }}"))), nil
+	}, "README.md.tmpl", Data{PROJECT_NAME: "my-app"})
+
+	rc, err := open()
+	require.NoError(t, err)
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "# my-app", string(content))
+}
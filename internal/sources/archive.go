@@ -0,0 +1,159 @@
+package sources
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/utils"
+)
+
+// Archive extensions recognised as template bundles.
+const (
+	// zipExt is the extension of zip archives.
+	zipExt = ".zip"
+	// tarGzExt is the extension of gzipped tarballs.
+	tarGzExt = ".tar.gz"
+	// tgzExt is the short extension of gzipped tarballs.
+	tgzExt = ".tgz"
+)
+
+// IsArchive reports whether location names a template bundle archive, based on its extension.
+// Parameters:
+// - location: A local path or an http(s) URL.
+// Returns: true if location ends with .zip, .tar.gz or .tgz.
+func IsArchive(location string) bool {
+	name := strings.ToLower(archivePath(location))
+	return strings.HasSuffix(name, zipExt) || strings.HasSuffix(name, tarGzExt) || strings.HasSuffix(name, tgzExt)
+}
+
+// archiveClient downloads remote archives. It never sends credentials and is separate from the shared httpclient.Client,
+// so downloading an archive layer does not leave the client of later GitHub, GitLab or Gitea layers without their token.
+var archiveClient httpclient.HTTPClient = &http.Client{}
+
+// isRemote reports whether location is an http or https URL.
+func isRemote(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// NewArchiveSource loads a .zip, .tar.gz or .tgz template bundle from a local path or an http(s) URL into memory.
+// When every file of the archive is wrapped in a single directory, as produced by e.g. `tar czf templates.tar.gz templates/`,
+// that directory is used as the template root. Dot directories such as .licenseFiles are never unwrapped.
+// Parameters:
+// - location: The local path or URL of the archive.
+// Returns: A MemorySource holding the archive contents and an error if the archive could not be read.
+func NewArchiveSource(location string) (*MemorySource, error) {
+	content, err := readArchive(location)
+	if err != nil {
+		return nil, err
+	}
+	return loadArchive(location, content)
+}
+
+// loadArchive loads the bytes of a .zip, .tar.gz or .tgz template bundle into memory, see NewArchiveSource.
+// Parameters:
+// - location: The local path or URL the archive was read from, whose extension selects the format.
+// - content: The bytes of the archive.
+// Returns: A MemorySource holding the archive contents and an error if the archive could not be read.
+func loadArchive(location string, content []byte) (*MemorySource, error) {
+	var (
+		src *MemorySource
+		err error
+	)
+	if strings.HasSuffix(strings.ToLower(archivePath(location)), zipExt) {
+		src, err = readZip(content)
+	} else {
+		src, err = readTarGz(bytes.NewReader(content), 0)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %v", location, err)
+	}
+
+	return src.unwrap(), nil
+}
+
+// archivePath returns the part of location that carries the archive extension.
+func archivePath(location string) string {
+	if isRemote(location) {
+		if u, err := url.Parse(location); err == nil {
+			return u.Path
+		}
+	}
+	return location
+}
+
+// readArchive reads the raw bytes of the archive at location, downloading it without credentials when it is a URL.
+// Parameters:
+// - location: The local path or URL of the archive.
+// Returns: The archive bytes and an error if it could not be read.
+func readArchive(location string) ([]byte, error) {
+	if !isRemote(location) {
+		content, err := os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open template archive %s: %v", location, err)
+		}
+		return content, nil
+	}
+
+	body, err := utils.DownloadWith(archiveClient, location)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+// readZip loads every regular file of a zip archive into a MemorySource.
+// Parameters:
+// - content: The bytes of the zip archive.
+// Returns: A MemorySource holding the archive contents and an error if the archive could not be read.
+func readZip(content []byte) (*MemorySource, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	src := newMemorySource()
+	for _, file := range zr.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %v", file.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Name, err)
+		}
+
+		src.add(file.Name, data)
+	}
+
+	return src, nil
+}
+
+// unwrap returns a MemorySource rooted at the single directory wrapping every file, or m itself when there is no such directory.
+func (m *MemorySource) unwrap() *MemorySource {
+	root := m.index[consts.EMPTY_STRING]
+	if len(root) != 1 || root[0].Type != consts.DIR_TYPE || strings.HasPrefix(root[0].Name, ".") {
+		return m
+	}
+
+	prefix := root[0].Name + "/"
+	unwrapped := newMemorySource()
+	for file, content := range m.files {
+		unwrapped.add(strings.TrimPrefix(file, prefix), content)
+	}
+	return unwrapped
+}
@@ -0,0 +1,142 @@
+package sources
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+// buildZip creates a zip archive holding the given files.
+func buildZip(t *testing.T, files [][2]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.Create(file[0])
+		require.NoError(t, err)
+		_, err = w.Write([]byte(file[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func TestIsArchive(t *testing.T) {
+	tests := []struct {
+		location string
+		expected bool
+	}{
+		{"./templates.zip", true},
+		{"./templates.tar.gz", true},
+		{"./TEMPLATES.TGZ", true},
+		{"https://example.com/templates.tar.gz?token=abc", true},
+		{"./templates", false},
+		{"https://example.com/templates", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsArchive(tt.location))
+		})
+	}
+}
+
+func TestNewArchiveSource(t *testing.T) {
+	files := [][2]string{
+		{"templates/README.md", "readme"},
+		{"templates/.licenseFiles/mit/LICENSE", "MIT"},
+	}
+
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "templates.zip")
+	tarPath := filepath.Join(dir, "templates.tar.gz")
+	flatPath := filepath.Join(dir, "flat.tgz")
+	require.NoError(t, os.WriteFile(zipPath, buildZip(t, files), 0644))
+	require.NoError(t, os.WriteFile(tarPath, buildTarGz(t, files), 0644))
+	require.NoError(t, os.WriteFile(flatPath, buildTarGz(t, [][2]string{{"./.licenseFiles/mit/LICENSE", "MIT"}}), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buildZip(t, files))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		location string
+		expected []types.TemplateItem
+	}{
+		{"Zip on disk", zipPath, []types.TemplateItem{
+			{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+			{Type: consts.FILE_TYPE, Name: "README.md", Path: "README.md"},
+		}},
+		{"Tarball on disk", tarPath, []types.TemplateItem{
+			{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+			{Type: consts.FILE_TYPE, Name: "README.md", Path: "README.md"},
+		}},
+		{"Zip from URL", server.URL + "/bundles/templates.zip", []types.TemplateItem{
+			{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+			{Type: consts.FILE_TYPE, Name: "README.md", Path: "README.md"},
+		}},
+		{"Dot directories are not unwrapped", flatPath, []types.TemplateItem{
+			{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := NewArchiveSource(tt.location)
+			require.NoError(t, err)
+
+			items, err := src.ReadDir(consts.EMPTY_STRING)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, items)
+
+			r, err := src.Open(".licenseFiles/mit/LICENSE")
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, "MIT", string(content))
+		})
+	}
+
+	_, err := NewArchiveSource(filepath.Join(dir, "missing.zip"))
+	assert.Error(t, err)
+}
+
+func TestNewArchiveSourceKeepsSharedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(string(httpclient.AuthorizationHeader)))
+		w.Write(buildZip(t, [][2]string{{"README.md", "readme"}}))
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+
+	// an archive layered before a remote source must not initialize the shared client without a token
+	httpclient.Client = nil
+	_, err := NewArchiveSource(server.URL + "/templates.zip")
+	require.NoError(t, err)
+	assert.Nil(t, httpclient.Client)
+
+	// nor send the token of an authenticated client to the archive host
+	require.NoError(t, httpclient.InitClient("secret"))
+	client := httpclient.Client
+	_, err = NewArchiveSource(server.URL + "/templates.zip")
+	require.NoError(t, err)
+	assert.Same(t, client, httpclient.Client)
+}
@@ -0,0 +1,83 @@
+package sources
+
+import (
+	"io"
+	"sync"
+
+	"github-project-template/internal/types"
+)
+
+// DeferredSource reads a template from a cheap source, enough to load the manifest and validate the variables it declares,
+// until Fetch builds the full source, e.g. reading stub.yaml through the Contents API before the tarball of the repository is downloaded.
+type DeferredSource struct {
+	mu      sync.Mutex
+	current TemplateSource
+	fetch   func() (TemplateSource, error)
+}
+
+// Defer creates a DeferredSource reading from light until Fetch replaces it with the source built by fetch.
+// Parameters:
+// - light: The source read from before Fetch, which must hold the same files as the full source.
+// - fetch: Builds the full source.
+// Returns: A pointer to the DeferredSource.
+func Defer(light TemplateSource, fetch func() (TemplateSource, error)) *DeferredSource {
+	return &DeferredSource{current: light, fetch: fetch}
+}
+
+// ReadDir lists the items directly beneath dir from the current source.
+// Parameters:
+// - dir: The slash separated directory relative to the template root.
+// Returns: The items found in the directory and an error if it could not be read.
+func (d *DeferredSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	return d.source().ReadDir(dir)
+}
+
+// Open opens the file at the given path from the current source.
+// Parameters:
+// - file: The slash separated path of the file relative to the template root.
+// Returns: The file contents and an error if it could not be opened.
+func (d *DeferredSource) Open(file string) (io.ReadCloser, error) {
+	return d.source().Open(file)
+}
+
+// source returns the source currently read from.
+func (d *DeferredSource) source() TemplateSource {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.current
+}
+
+// Fetch builds the full source of every DeferredSource within src, e.g. downloading a tarball or hashing a local directory.
+// Sources that were fetched already are left as they are.
+// Parameters:
+// - src: The template source.
+// Returns: An error if a full source could not be built.
+func Fetch(src TemplateSource) error {
+	switch s := src.(type) {
+	case *DeferredSource:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.fetch == nil {
+			return nil
+		}
+		full, err := s.fetch()
+		if err != nil {
+			return err
+		}
+		s.current, s.fetch = full, nil
+		return nil
+	case *OriginSource:
+		return Fetch(s.TemplateSource)
+	case *SubSource:
+		return Fetch(s.src)
+	case *LayeredSource:
+		for _, layer := range s.layers {
+			if err := Fetch(layer.Source); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
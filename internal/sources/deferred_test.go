@@ -0,0 +1,107 @@
+package sources
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+func TestFetch(t *testing.T) {
+	fetches := 0
+	full := WithOrigin(NewMemorySource(map[string]string{"stub.yaml": "full"}), Origin{Source: "/templates", SHA: "sha256:1"})
+	deferred := Defer(NewMemorySource(map[string]string{"stub.yaml": "light"}), func() (TemplateSource, error) {
+		fetches++
+		return full, nil
+	})
+	src := NewLayeredSource(Layer{Name: "base", Source: NewMemorySource(nil)}, Layer{Name: "overlay", Source: Sub(deferred, consts.EMPTY_STRING)})
+
+	assert.Nil(t, Origins(src))
+	r, err := deferred.Open("stub.yaml")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(r)
+	assert.Equal(t, "light", string(content))
+
+	require.NoError(t, Fetch(src))
+	require.NoError(t, Fetch(src))
+	assert.Equal(t, 1, fetches, "a source is only fetched once")
+	assert.Equal(t, []Origin{{Source: "/templates", SHA: "sha256:1"}}, Origins(src))
+	r, err = deferred.Open("stub.yaml")
+	require.NoError(t, err)
+	content, _ = io.ReadAll(r)
+	assert.Equal(t, "full", string(content))
+}
+
+func TestNewLocalDirDefersHashing(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, consts.MANIFEST), []byte("variables: []"), 0644))
+
+	src, err := New(types.CliFlags{Sources: []string{dir}})
+	require.NoError(t, err)
+	assert.Nil(t, Origins(src), "the directory is not hashed before it is fetched")
+	_, err = src.Open(consts.MANIFEST)
+	require.NoError(t, err)
+
+	require.NoError(t, Fetch(src))
+	origins := Origins(src)
+	require.Len(t, origins, 1)
+	assert.True(t, origins[0].HasContentSHA())
+}
+
+func TestNewGitHubTarballDefersDownload(t *testing.T) {
+	archive := buildTarGz(t, [][2]string{
+		{"acme-templates-abc123/stub.yaml", "variables: []"},
+		{"acme-templates-abc123/README.md", "readme"},
+	})
+
+	tarballs := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/acme/templates/tarball/abc123":
+			tarballs++
+			w.Write(archive)
+		case r.URL.Path == "/repos/acme/templates/contents":
+			assert.Equal(t, "abc123", r.URL.Query().Get("ref"))
+			json.NewEncoder(w).Encode([]types.GitHubItem{{Type: consts.FILE_TYPE, Name: consts.MANIFEST, Path: consts.MANIFEST, DownloadURL: server.URL + "/raw/stub.yaml"}})
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			w.Write([]byte("variables: []"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	httpclient.Client = server.Client()
+
+	src, err := newGitHub(types.CliFlags{ApiUrl: server.URL, RepoOwner: "acme", RepoName: "templates", BranchName: "abc123", FetchMode: consts.FETCH_TARBALL})
+	require.NoError(t, err)
+
+	items, err := src.ReadDir(consts.EMPTY_STRING)
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{{Type: consts.FILE_TYPE, Name: consts.MANIFEST, Path: consts.MANIFEST}}, items)
+	r, err := src.Open(consts.MANIFEST)
+	require.NoError(t, err)
+	content, _ := io.ReadAll(r)
+	assert.Equal(t, "variables: []", string(content))
+	assert.Equal(t, 0, tarballs, "the tarball is not downloaded before it is fetched")
+
+	require.NoError(t, Fetch(src))
+	assert.Equal(t, 1, tarballs)
+	items, err = src.ReadDir(consts.EMPTY_STRING)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
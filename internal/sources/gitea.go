@@ -0,0 +1,156 @@
+package sources
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+// giteaPageSize is the number of entries requested per page from list endpoints.
+const giteaPageSize = 50
+
+// GiteaSource reads a template from a Gitea or Forgejo repository through the contents API.
+// The responses share their shape with GitHub, so entries are decoded as types.GitHubItem.
+type GiteaSource struct {
+	// repoUrl is the API URL of the repository, e.g. https://gitea.example.com/api/v1/repos/owner/name.
+	repoUrl string
+	// ref is the branch, tag or commit the contents are read from.
+	ref string
+
+	mu sync.Mutex
+	// downloadUrls caches the download URL of every file seen while listing directories.
+	downloadUrls map[string]string
+}
+
+// NewGiteaSource creates a GiteaSource for the given repository, initializing the shared HTTP client if needed.
+// Parameters:
+// - apiUrl: The base URL of the Gitea or Forgejo instance, with or without the /api/v1 suffix.
+// - owner: The owner of the repository.
+// - name: The name of the repository.
+// - ref: The branch, tag or commit to read from; an empty ref uses the repository default.
+// - token: The access token used to authenticate requests.
+// Returns: A pointer to the GiteaSource and an error if no API URL is given or the HTTP client could not be initialized.
+func NewGiteaSource(apiUrl, owner, name, ref, token string) (*GiteaSource, error) {
+	if apiUrl == consts.EMPTY_STRING {
+		return nil, fmt.Errorf("provider %s requires an API URL", consts.PROVIDER_GITEA)
+	}
+
+	if err := ensureClient(token, httpclient.AuthorizationHeader, apiUrl); err != nil {
+		return nil, err
+	}
+
+	return &GiteaSource{
+		repoUrl:      giteaRepoUrl(apiUrl, owner, name),
+		ref:          ref,
+		downloadUrls: make(map[string]string),
+	}, nil
+}
+
+// giteaApiUrl normalizes the configured Gitea URL to the v1 API root.
+func giteaApiUrl(apiUrl string) string {
+	apiUrl = strings.TrimSuffix(apiUrl, "/")
+	if !strings.HasSuffix(apiUrl, "/api/v1") {
+		apiUrl += "/api/v1"
+	}
+	return apiUrl
+}
+
+// giteaRepoUrl builds the API URL of a repository on the given Gitea or Forgejo instance.
+func giteaRepoUrl(apiUrl, owner, name string) string {
+	return fmt.Sprintf("%s/repos/%s/%s", giteaApiUrl(apiUrl), url.PathEscape(owner), url.PathEscape(name))
+}
+
+// ReadDir lists the contents of the given directory, following the Link header through every page of results.
+// Parameters:
+// - dir: The slash separated directory relative to the repository root.
+// Returns: The items found in the directory and an error if a request fails.
+func (g *GiteaSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	var items []types.TemplateItem
+
+	for next := g.contentsUrl(dir); next != consts.EMPTY_STRING; {
+		var contents []types.GitHubItem
+		header, err := getJSONWithHeaders(next, &contents)
+		if err != nil {
+			return nil, err
+		}
+
+		g.mu.Lock()
+		for _, content := range contents {
+			if content.Type == consts.FILE_TYPE {
+				g.downloadUrls[content.Path] = content.DownloadURL
+			}
+			items = append(items, types.TemplateItem{Type: content.Type, Name: content.Name, Path: content.Path})
+		}
+		g.mu.Unlock()
+
+		next = nextPageUrl(header)
+	}
+
+	return items, nil
+}
+
+// Open downloads the file at the given path, using the raw endpoint when the file has not been listed yet.
+// Parameters:
+// - file: The slash separated path of the file relative to the repository root.
+// Returns: The raw file contents and an error if the file could not be fetched.
+func (g *GiteaSource) Open(file string) (io.ReadCloser, error) {
+	g.mu.Lock()
+	downloadUrl, ok := g.downloadUrls[file]
+	g.mu.Unlock()
+
+	if !ok || downloadUrl == consts.EMPTY_STRING {
+		downloadUrl = g.refUrl(fmt.Sprintf("%s/raw/%s", g.repoUrl, file), url.Values{})
+	}
+
+	resp, err := doRequest(downloadUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// contentsUrl builds the contents API URL for the first page of the given path, including the ref when one is set.
+func (g *GiteaSource) contentsUrl(p string) string {
+	query := url.Values{}
+	query.Set("page", "1")
+	query.Set("limit", fmt.Sprint(giteaPageSize))
+	return g.refUrl(appendPathToUrl(fmt.Sprintf("%s/contents", g.repoUrl), p), query)
+}
+
+// refUrl appends the ref, when one is set, and the given query parameters to u.
+func (g *GiteaSource) refUrl(u string, query url.Values) string {
+	if g.ref != consts.EMPTY_STRING {
+		query.Set("ref", g.ref)
+	}
+	if len(query) == 0 {
+		return u
+	}
+	return fmt.Sprintf("%s?%s", u, query.Encode())
+}
+
+// nextPageUrl extracts the rel="next" target from an RFC 8288 Link header, as sent by paginated Gitea and GitHub endpoints.
+// Parameters:
+// - header: The response headers.
+// Returns: The URL of the next page, or an empty string when there is none.
+func nextPageUrl(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+		for _, param := range parts[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
+	}
+	return consts.EMPTY_STRING
+}
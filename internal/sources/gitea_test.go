@@ -0,0 +1,109 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+func TestNextPageUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{"No header", consts.EMPTY_STRING, consts.EMPTY_STRING},
+		{"Next page", `<https://example.com/a?page=2>; rel="next", <https://example.com/a?page=3>; rel="last"`, "https://example.com/a?page=2"},
+		{"Last page", `<https://example.com/a?page=1>; rel="first", <https://example.com/a?page=2>; rel="prev"`, consts.EMPTY_STRING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.link != consts.EMPTY_STRING {
+				header.Set("Link", tt.link)
+			}
+			assert.Equal(t, tt.expected, nextPageUrl(header))
+		})
+	}
+}
+
+func TestNewGiteaSourceRequiresApiUrl(t *testing.T) {
+	src, err := NewGiteaSource(consts.EMPTY_STRING, "owner", "repo", "main", consts.EMPTY_STRING)
+	assert.Error(t, err)
+	assert.Nil(t, src)
+}
+
+func TestGiteaSource(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/api/v1/repos/tools/templates/contents/.workflowFiles/go":
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?limit=50&page=2&ref=main>; rel="next"`, server.URL, r.URL.Path))
+				json.NewEncoder(w).Encode([]types.GitHubItem{{Type: consts.FILE_TYPE, Name: "build.yml", Path: ".workflowFiles/go/build.yml", DownloadURL: server.URL + "/download/build.yml"}})
+				return
+			}
+			json.NewEncoder(w).Encode([]types.GitHubItem{{Type: consts.DIR_TYPE, Name: "nested", Path: ".workflowFiles/go/nested"}})
+		case "/download/build.yml":
+			w.Write([]byte("name: build"))
+		case "/api/v1/repos/tools/templates/raw/.licenseFiles/mit/LICENSE":
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			w.Write([]byte("MIT"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	require.NoError(t, httpclient.InitClient("secret"))
+
+	src, err := NewGiteaSource(server.URL+"/", "tools", "templates", "main", "secret")
+	require.NoError(t, err)
+
+	items, err := src.ReadDir(".workflowFiles/go")
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{
+		{Type: consts.FILE_TYPE, Name: "build.yml", Path: ".workflowFiles/go/build.yml"},
+		{Type: consts.DIR_TYPE, Name: "nested", Path: ".workflowFiles/go/nested"},
+	}, items)
+
+	tests := []struct {
+		name     string
+		file     string
+		expected string
+	}{
+		{"Listed file uses its download url", ".workflowFiles/go/build.yml", "name: build"},
+		{"Unlisted file uses the raw endpoint", ".licenseFiles/mit/LICENSE", "MIT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := src.Open(tt.file)
+			require.NoError(t, err)
+			defer r.Close()
+
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(content))
+		})
+	}
+
+	_, err = src.Open("missing")
+	assert.Error(t, err)
+}
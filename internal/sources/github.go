@@ -0,0 +1,143 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+	"github-project-template/internal/utils"
+)
+
+// GitHubSource reads a template from a GitHub repository through the Contents API.
+type GitHubSource struct {
+	// baseUrl is the contents endpoint of the repository, e.g. https://api.github.com/repos/owner/name/contents.
+	baseUrl string
+	// ref is the branch, tag or commit the contents are read from.
+	ref string
+
+	mu sync.Mutex
+	// downloadUrls caches the download URL of every file seen while listing directories.
+	downloadUrls map[string]string
+}
+
+// NewGitHubSource creates a GitHubSource for the given contents endpoint and ref, initializing the shared HTTP client if needed.
+// Parameters:
+// - baseUrl: The contents endpoint of the repository.
+// - ref: The branch, tag or commit to read from; an empty ref uses the repository default.
+// - token: The GitHub token used to authenticate requests.
+// Returns: A pointer to the GitHubSource and an error if the HTTP client could not be initialized.
+func NewGitHubSource(baseUrl, ref, token string) (*GitHubSource, error) {
+	if err := ensureClient(token, httpclient.AuthorizationHeader, baseUrl); err != nil {
+		return nil, err
+	}
+
+	return &GitHubSource{
+		baseUrl:      baseUrl,
+		ref:          ref,
+		downloadUrls: make(map[string]string),
+	}, nil
+}
+
+// ReadDir lists the contents of the given directory using the Contents API.
+// Parameters:
+// - dir: The slash separated directory relative to the repository root.
+// Returns: The items found in the directory and an error if the request fails.
+func (g *GitHubSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	contents, err := getRepoContents(g.contentsUrl(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	items := make([]types.TemplateItem, 0, len(contents))
+	for _, content := range contents {
+		if content.Type == consts.FILE_TYPE {
+			g.downloadUrls[content.Path] = content.DownloadURL
+		}
+		items = append(items, types.TemplateItem{Type: content.Type, Name: content.Name, Path: content.Path})
+	}
+
+	return items, nil
+}
+
+// Open downloads the file at the given path, looking up its download URL first if it has not been listed yet.
+// Parameters:
+// - file: The slash separated path of the file relative to the repository root.
+// Returns: The body of the download and an error if the file could not be fetched.
+func (g *GitHubSource) Open(file string) (io.ReadCloser, error) {
+	g.mu.Lock()
+	downloadUrl, ok := g.downloadUrls[file]
+	g.mu.Unlock()
+
+	if !ok {
+		var err error
+		if downloadUrl, err = utils.GrabDownloadUrl(g.contentsUrl(file)); err != nil {
+			return nil, err
+		}
+		if downloadUrl == consts.EMPTY_STRING {
+			return nil, fmt.Errorf("no download url found for %s", file)
+		}
+	}
+
+	return utils.Download(downloadUrl)
+}
+
+// contentsUrl builds the Contents API URL for the given path, including the ref when one is set.
+func (g *GitHubSource) contentsUrl(p string) string {
+	contentsUrl := appendPathToUrl(g.baseUrl, p)
+	if g.ref != consts.EMPTY_STRING {
+		contentsUrl = fmt.Sprintf("%s?ref=%s", contentsUrl, url.QueryEscape(g.ref))
+	}
+	return contentsUrl
+}
+
+// getRepoContents retrieves the listing at the given Contents API URL.
+// A single file response is returned as a one element slice.
+// Parameters:
+// - url: The Contents API URL to request.
+// Returns: The decoded items and an error if the request or decoding fails.
+func getRepoContents(url string) ([]types.GitHubItem, error) {
+	resp, err := doRequest(url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var contents []types.GitHubItem
+	err = json.Unmarshal(body, &contents)
+	if err != nil {
+		var singleItem types.GitHubItem
+		err = json.Unmarshal(body, &singleItem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response %s: %v", url, err)
+		}
+		return []types.GitHubItem{singleItem}, nil
+	}
+
+	return contents, nil
+}
+
+// appendPathToUrl appends the given path to the URL if the path is not an empty string.
+// If the path is not empty, it ensures the URL is properly formatted with a "/" between the base URL and the path.
+// Parameters:
+// - url: The base URL as a string.
+// - path: The path to append to the URL as a string.
+// Returns: A string representing the full URL with the appended path.
+func appendPathToUrl(url, path string) string {
+	if path != consts.EMPTY_STRING {
+		url = fmt.Sprintf("%s/%s", url, path)
+	}
+	return url
+}
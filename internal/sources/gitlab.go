@@ -0,0 +1,114 @@
+package sources
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+// gitLabPageSize is the number of tree entries requested per page, the maximum GitLab allows.
+const gitLabPageSize = 100
+
+// GitLabSource reads a template from a GitLab project through the repository tree and raw file endpoints.
+type GitLabSource struct {
+	// projectUrl is the API URL of the project, e.g. https://gitlab.com/api/v4/projects/owner%2Fname.
+	projectUrl string
+	// ref is the branch, tag or commit the contents are read from.
+	ref string
+}
+
+// NewGitLabSource creates a GitLabSource for the given project, initializing the shared HTTP client with PRIVATE-TOKEN authentication if needed.
+// Parameters:
+// - apiUrl: The base URL of the GitLab instance, with or without the /api/v4 suffix; empty uses gitlab.com.
+// - project: The full path of the project, e.g. owner/name or group/subgroup/name.
+// - ref: The branch, tag or commit to read from; an empty ref uses the project default.
+// - token: The GitLab token used to authenticate requests.
+// Returns: A pointer to the GitLabSource and an error if the HTTP client could not be initialized.
+func NewGitLabSource(apiUrl, project, ref, token string) (*GitLabSource, error) {
+	apiUrl = gitLabApiUrl(apiUrl)
+	if err := ensureClient(token, httpclient.PrivateTokenHeader, apiUrl); err != nil {
+		return nil, err
+	}
+
+	return &GitLabSource{
+		projectUrl: gitLabProjectUrl(apiUrl, project),
+		ref:        ref,
+	}, nil
+}
+
+// gitLabApiUrl normalizes the configured GitLab URL to the v4 API root.
+func gitLabApiUrl(apiUrl string) string {
+	if apiUrl == consts.EMPTY_STRING {
+		apiUrl = consts.GITLAB_API_URL
+	}
+
+	apiUrl = strings.TrimSuffix(apiUrl, "/")
+	if !strings.HasSuffix(apiUrl, "/api/v4") {
+		apiUrl += "/api/v4"
+	}
+	return apiUrl
+}
+
+// gitLabProjectUrl builds the API URL of a project from the v4 API root and the full project path.
+func gitLabProjectUrl(apiUrl, project string) string {
+	return fmt.Sprintf("%s/projects/%s", apiUrl, url.PathEscape(project))
+}
+
+// ReadDir lists the contents of the given directory using the repository tree endpoint, following every page of results.
+// Parameters:
+// - dir: The slash separated directory relative to the project root.
+// Returns: The items found in the directory and an error if a request fails.
+func (g *GitLabSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	var items []types.TemplateItem
+
+	for page := "1"; page != consts.EMPTY_STRING; {
+		query := url.Values{}
+		query.Set("path", dir)
+		query.Set("per_page", fmt.Sprint(gitLabPageSize))
+		query.Set("page", page)
+		if g.ref != consts.EMPTY_STRING {
+			query.Set("ref", g.ref)
+		}
+
+		var entries []types.GitLabItem
+		header, err := getJSONWithHeaders(fmt.Sprintf("%s/repository/tree?%s", g.projectUrl, query.Encode()), &entries)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			itemType := consts.FILE_TYPE
+			if entry.Type == consts.TREE_TYPE {
+				itemType = consts.DIR_TYPE
+			}
+			items = append(items, types.TemplateItem{Type: itemType, Name: entry.Name, Path: entry.Path})
+		}
+
+		page = header.Get("X-Next-Page")
+	}
+
+	return items, nil
+}
+
+// Open downloads the raw contents of the file at the given path.
+// Parameters:
+// - file: The slash separated path of the file relative to the project root.
+// Returns: The raw file contents and an error if the file could not be fetched.
+func (g *GitLabSource) Open(file string) (io.ReadCloser, error) {
+	fileUrl := fmt.Sprintf("%s/repository/files/%s/raw", g.projectUrl, url.PathEscape(file))
+	if g.ref != consts.EMPTY_STRING {
+		fileUrl = fmt.Sprintf("%s?ref=%s", fileUrl, url.QueryEscape(g.ref))
+	}
+
+	resp, err := doRequest(fileUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
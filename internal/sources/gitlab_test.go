@@ -0,0 +1,81 @@
+package sources
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+func TestGitLabApiUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiUrl   string
+		expected string
+	}{
+		{"Default instance", consts.EMPTY_STRING, "https://gitlab.com/api/v4"},
+		{"Self-managed instance", "https://gitlab.example.com", "https://gitlab.example.com/api/v4"},
+		{"Trailing slash", "https://gitlab.example.com/", "https://gitlab.example.com/api/v4"},
+		{"Already versioned", "https://gitlab.example.com/api/v4", "https://gitlab.example.com/api/v4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, gitLabApiUrl(tt.apiUrl))
+		})
+	}
+}
+
+func TestGitLabSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/acme%2Ftemplates/repository/tree":
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			assert.Equal(t, ".workflowFiles/go", r.URL.Query().Get("path"))
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				json.NewEncoder(w).Encode([]types.GitLabItem{{Name: "build.yml", Type: consts.BLOB_TYPE, Path: ".workflowFiles/go/build.yml"}})
+				return
+			}
+			json.NewEncoder(w).Encode([]types.GitLabItem{{Name: "nested", Type: consts.TREE_TYPE, Path: ".workflowFiles/go/nested"}})
+		case "/api/v4/projects/acme%2Ftemplates/repository/files/.licenseFiles%2Fmit%2FLICENSE/raw":
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			w.Write([]byte("MIT"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	httpclient.Client = server.Client()
+
+	src, err := NewGitLabSource(server.URL, "acme/templates", "main", consts.EMPTY_STRING)
+	require.NoError(t, err)
+
+	items, err := src.ReadDir(".workflowFiles/go")
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{
+		{Type: consts.FILE_TYPE, Name: "build.yml", Path: ".workflowFiles/go/build.yml"},
+		{Type: consts.DIR_TYPE, Name: "nested", Path: ".workflowFiles/go/nested"},
+	}, items)
+
+	r, err := src.Open(".licenseFiles/mit/LICENSE")
+	require.NoError(t, err)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", string(content))
+
+	_, err = src.Open("missing")
+	assert.Error(t, err)
+}
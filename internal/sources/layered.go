@@ -0,0 +1,161 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+// Layer is a named TemplateSource taking part in a LayeredSource.
+type Layer struct {
+	// Name identifies the layer in reports, e.g. the --source value it was created from.
+	Name string
+	// Source is the template source of the layer.
+	Source TemplateSource
+}
+
+// LayerResolver is implemented by sources that merge several layers and can report which layer provides a file.
+type LayerResolver interface {
+	// LayerOf returns the name of the layer the file at path is read from.
+	LayerOf(path string) (string, error)
+}
+
+// LayeredSource merges several template sources into a single view.
+// Files and directories of later layers override those of earlier layers with the same path, and everything else falls through.
+type LayeredSource struct {
+	layers []Layer
+
+	mu sync.Mutex
+	// owners maps the path of every file seen while listing to the index of the layer providing it.
+	owners map[string]int
+}
+
+// NewLayeredSource creates a LayeredSource from layers ordered from lowest to highest precedence.
+// Parameters:
+// - layers: The layers to merge, where later layers override earlier ones.
+// Returns: A pointer to the LayeredSource.
+func NewLayeredSource(layers ...Layer) *LayeredSource {
+	return &LayeredSource{
+		layers: layers,
+		owners: make(map[string]int),
+	}
+}
+
+// ReadDir merges the listings of dir from every layer that contains it.
+// Layers without the directory, i.e. failing with fs.ErrNotExist, are skipped; any other failure of a layer, e.g. a network error,
+// fails the listing, so a layer is never silently left out.
+// Parameters:
+// - dir: The slash separated directory relative to the template root.
+// Returns: The merged items and an error if a layer could not be read or no layer contains the directory.
+func (l *LayeredSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	var (
+		items   []types.TemplateItem
+		indexes = make(map[string]int)
+		owners  = make(map[string]int)
+		lastErr error
+		found   bool
+	)
+
+	for i, layer := range l.layers {
+		layerItems, err := layer.Source.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("template source %s: %w", layer.Name, err)
+		}
+		found = true
+
+		for _, item := range layerItems {
+			if idx, exists := indexes[item.Name]; exists {
+				items[idx] = item
+			} else {
+				indexes[item.Name] = len(items)
+				items = append(items, item)
+			}
+
+			if item.Type == consts.FILE_TYPE {
+				owners[item.Path] = i
+			} else {
+				delete(owners, item.Path)
+			}
+		}
+	}
+
+	if !found {
+		return nil, lastErr
+	}
+
+	l.mu.Lock()
+	for file, owner := range owners {
+		l.owners[file] = owner
+	}
+	l.mu.Unlock()
+
+	return items, nil
+}
+
+// Open opens the file at the given path from the layer with the highest precedence that provides it.
+// Parameters:
+// - file: The slash separated path of the file relative to the template root.
+// Returns: The file contents and an error if no layer provides the file.
+func (l *LayeredSource) Open(file string) (io.ReadCloser, error) {
+	owner, err := l.owner(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.layers[owner].Source.Open(file)
+}
+
+// LayerOf returns the name of the layer the file at path is read from.
+// Parameters:
+// - file: The slash separated path of the file relative to the template root.
+// Returns: The name of the layer and an error if no layer provides the file.
+func (l *LayeredSource) LayerOf(file string) (string, error) {
+	owner, err := l.owner(file)
+	if err != nil {
+		return consts.EMPTY_STRING, err
+	}
+
+	return l.layers[owner].Name, nil
+}
+
+// owner returns the index of the layer providing file, listing its parent directory if the file has not been seen yet.
+func (l *LayeredSource) owner(file string) (int, error) {
+	file = cleanPath(file)
+
+	l.mu.Lock()
+	owner, ok := l.owners[file]
+	l.mu.Unlock()
+	if ok {
+		return owner, nil
+	}
+
+	parent := path.Dir(file)
+	if parent == "." {
+		parent = consts.EMPTY_STRING
+	}
+	if _, err := l.ReadDir(parent); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("file not found in any template source: %s: %w", file, fs.ErrNotExist)
+	}
+
+	l.mu.Lock()
+	owner, ok = l.owners[file]
+	l.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("file not found in any template source: %s: %w", file, fs.ErrNotExist)
+	}
+
+	return owner, nil
+}
@@ -0,0 +1,131 @@
+package sources
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+func TestLayeredSource(t *testing.T) {
+	base := NewMemorySource(map[string]string{
+		".licenseFiles/mit/LICENSE":    "base MIT",
+		".licenseFiles/apache/LICENSE": "base Apache",
+		".workflowFiles/go/test.yml":   "base test",
+		"README.md":                    "base readme",
+	})
+	overlay := NewMemorySource(map[string]string{
+		".licenseFiles/mit/LICENSE":     "team MIT",
+		".workflowFiles/go/release.yml": "team release",
+	})
+
+	src := NewLayeredSource(Layer{Name: "org/base", Source: base}, Layer{Name: "team/overlay", Source: overlay})
+
+	items, err := src.ReadDir(".workflowFiles/go")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.TemplateItem{
+		{Type: consts.FILE_TYPE, Name: "test.yml", Path: ".workflowFiles/go/test.yml"},
+		{Type: consts.FILE_TYPE, Name: "release.yml", Path: ".workflowFiles/go/release.yml"},
+	}, items)
+
+	tests := []struct {
+		name            string
+		file            string
+		expectedContent string
+		expectedLayer   string
+	}{
+		{"Overridden file", ".licenseFiles/mit/LICENSE", "team MIT", "team/overlay"},
+		{"Fall through file", ".licenseFiles/apache/LICENSE", "base Apache", "org/base"},
+		{"Overlay only file", ".workflowFiles/go/release.yml", "team release", "team/overlay"},
+		{"Root file", "README.md", "base readme", "org/base"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layer, err := src.LayerOf(tt.file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLayer, layer)
+
+			r, err := src.Open(tt.file)
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContent, string(content))
+		})
+	}
+
+	_, err = src.Open(".licenseFiles/gpl/LICENSE")
+	assert.Error(t, err)
+	_, err = src.ReadDir("missing")
+	assert.Error(t, err)
+}
+
+func TestLayeredSourceLayerErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/missing"), strings.HasSuffix(r.URL.Path, "/absent"):
+			http.Error(w, "not found", http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "/broken"):
+			http.Error(w, "server error", http.StatusInternalServerError)
+		case strings.HasSuffix(r.URL.Path, "/private"):
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			w.Write([]byte("[]"))
+		}
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	require.NoError(t, httpclient.InitClient(consts.EMPTY_STRING))
+
+	base := NewMemorySource(map[string]string{
+		"missing/LICENSE": "base",
+		"broken/LICENSE":  "base",
+		"private/LICENSE": "base",
+	})
+	overlay, err := NewGitHubSource(server.URL, consts.EMPTY_STRING, consts.EMPTY_STRING)
+	require.NoError(t, err)
+	src := NewLayeredSource(Layer{Name: "org/base", Source: base}, Layer{Name: "team/overlay", Source: overlay})
+
+	tests := []struct {
+		name        string
+		dir         string
+		expectedErr bool
+	}{
+		{"Directory missing from the overlay", "missing", false},
+		{"Server error", "broken", true},
+		{"Forbidden", "private", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := src.ReadDir(tt.dir)
+			_, openErr := src.Open(tt.dir + "/LICENSE")
+			if tt.expectedErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "team/overlay")
+				assert.False(t, errors.Is(err, fs.ErrNotExist))
+				assert.Error(t, openErr)
+				assert.False(t, errors.Is(openErr, fs.ErrNotExist))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []types.TemplateItem{{Type: consts.FILE_TYPE, Name: "LICENSE", Path: tt.dir + "/LICENSE"}}, items)
+			assert.NoError(t, openErr)
+		})
+	}
+
+	_, err = src.ReadDir("absent")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
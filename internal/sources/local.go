@@ -0,0 +1,80 @@
+package sources
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+// gitDir is the name of the git metadata directory, which is never part of a template.
+const gitDir = ".git"
+
+// LocalSource reads a template from a directory on the local filesystem.
+type LocalSource struct {
+	root string
+}
+
+// NewLocalSource creates a LocalSource rooted at the given directory.
+// Parameters:
+// - root: The directory containing the template layout.
+// Returns: A pointer to the LocalSource and an error if root is not a directory.
+func NewLocalSource(root string) (*LocalSource, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open template source %s: %v", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template source %s is not a directory", root)
+	}
+
+	return &LocalSource{root: root}, nil
+}
+
+// ReadDir lists the files and directories directly beneath dir, skipping any git metadata.
+// Parameters:
+// - dir: The slash separated directory relative to the template root.
+// Returns: The items found in the directory and an error if it could not be read.
+func (l *LocalSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	entries, err := os.ReadDir(l.resolve(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]types.TemplateItem, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name() == gitDir {
+			continue
+		}
+
+		itemType := consts.FILE_TYPE
+		if entry.IsDir() {
+			itemType = consts.DIR_TYPE
+		}
+
+		items = append(items, types.TemplateItem{
+			Type: itemType,
+			Name: entry.Name(),
+			Path: path.Join(dir, entry.Name()),
+		})
+	}
+
+	return items, nil
+}
+
+// Open opens the file at the given slash separated path relative to the template root.
+// Parameters:
+// - file: The path of the file to open.
+// Returns: The opened file and an error if it could not be opened.
+func (l *LocalSource) Open(file string) (io.ReadCloser, error) {
+	return os.Open(l.resolve(file))
+}
+
+// resolve converts a slash separated template path into a path on the local filesystem.
+func (l *LocalSource) resolve(p string) string {
+	return filepath.Join(l.root, filepath.FromSlash(p))
+}
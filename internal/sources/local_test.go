@@ -0,0 +1,100 @@
+package sources
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+// writeTemplate creates the given files beneath root, creating parent directories as needed.
+func writeTemplate(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+}
+
+func TestNewLocalSource(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, map[string]string{"file.txt": "content"})
+
+	tests := []struct {
+		name          string
+		root          string
+		expectedError bool
+	}{
+		{"Existing directory", root, false},
+		{"Missing directory", filepath.Join(root, "missing"), true},
+		{"File instead of directory", filepath.Join(root, "file.txt"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := NewLocalSource(tt.root)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, src)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, src)
+			}
+		})
+	}
+}
+
+func TestLocalSourceReadDir(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, map[string]string{
+		".licenseFiles/mit/LICENSE": "MIT",
+		".git/HEAD":                 "ref: refs/heads/master",
+		"README.md":                 "readme",
+	})
+
+	src, err := NewLocalSource(root)
+	require.NoError(t, err)
+
+	items, err := src.ReadDir(consts.EMPTY_STRING)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.TemplateItem{
+		{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+		{Type: consts.FILE_TYPE, Name: "README.md", Path: "README.md"},
+	}, items)
+
+	items, err = src.ReadDir(".licenseFiles/mit")
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{
+		{Type: consts.FILE_TYPE, Name: "LICENSE", Path: ".licenseFiles/mit/LICENSE"},
+	}, items)
+
+	_, err = src.ReadDir("missing")
+	assert.Error(t, err)
+}
+
+func TestLocalSourceOpen(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, map[string]string{".licenseFiles/mit/LICENSE": "MIT"})
+
+	src, err := NewLocalSource(root)
+	require.NoError(t, err)
+
+	r, err := src.Open(".licenseFiles/mit/LICENSE")
+	require.NoError(t, err)
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", string(content))
+
+	_, err = src.Open("missing")
+	assert.Error(t, err)
+}
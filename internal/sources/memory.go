@@ -0,0 +1,128 @@
+package sources
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+// dirIndex maps every directory ("" for the root) to the items directly beneath it, for sources that know their whole tree up front.
+type dirIndex map[string][]types.TemplateItem
+
+// newDirIndex creates an index containing only the root directory.
+func newDirIndex() dirIndex {
+	return dirIndex{consts.EMPTY_STRING: nil}
+}
+
+// addFile registers a file at the given path along with every parent directory.
+// Parameters:
+// - file: The slash separated path of the file relative to the template root.
+// Returns: The cleaned path the file was registered under.
+func (d dirIndex) addFile(file string) string {
+	file = cleanPath(file)
+	d.addItem(types.TemplateItem{Type: consts.FILE_TYPE, Name: path.Base(file), Path: file})
+	return file
+}
+
+// addItem links item into its parent directory, creating the parent chain if it does not exist yet.
+func (d dirIndex) addItem(item types.TemplateItem) {
+	parent := path.Dir(item.Path)
+	if parent == "." {
+		parent = consts.EMPTY_STRING
+	}
+
+	if _, exists := d[parent]; !exists {
+		d[parent] = nil
+		d.addItem(types.TemplateItem{Type: consts.DIR_TYPE, Name: path.Base(parent), Path: parent})
+	}
+
+	for _, existing := range d[parent] {
+		if existing.Name == item.Name {
+			return
+		}
+	}
+	d[parent] = append(d[parent], item)
+}
+
+// readDir lists the items directly beneath dir, sorted by name.
+// Parameters:
+// - dir: The slash separated directory relative to the template root.
+// Returns: The items found in the directory and an error if the directory does not exist.
+func (d dirIndex) readDir(dir string) ([]types.TemplateItem, error) {
+	items, ok := d[cleanPath(dir)]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: dir, Err: fs.ErrNotExist}
+	}
+
+	sorted := append([]types.TemplateItem(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted, nil
+}
+
+// cleanPath normalizes a slash separated template path, mapping the root to an empty string.
+func cleanPath(p string) string {
+	return strings.Trim(path.Clean("/"+p), "/")
+}
+
+// MemorySource serves a template that has been fully loaded into memory, e.g. from a downloaded archive.
+type MemorySource struct {
+	// files maps the slash separated path of every file to its contents.
+	files map[string][]byte
+	// index holds the directory structure of the template.
+	index dirIndex
+}
+
+// NewMemorySource creates a MemorySource holding the given files, e.g. a template built in memory for a test.
+// Parameters:
+// - files: The contents of every file, keyed by its slash separated path relative to the template root.
+// Returns: A pointer to the MemorySource.
+func NewMemorySource(files map[string]string) *MemorySource {
+	m := newMemorySource()
+	for file, content := range files {
+		m.add(file, []byte(content))
+	}
+	return m
+}
+
+// newMemorySource creates an empty MemorySource containing only the root directory.
+func newMemorySource() *MemorySource {
+	return &MemorySource{
+		files: make(map[string][]byte),
+		index: newDirIndex(),
+	}
+}
+
+// add stores a file at the given path, registering every parent directory along the way.
+// Parameters:
+// - file: The slash separated path of the file relative to the template root.
+// - content: The contents of the file.
+func (m *MemorySource) add(file string, content []byte) {
+	m.files[m.index.addFile(file)] = content
+}
+
+// ReadDir lists the items directly beneath dir, sorted by name.
+// Parameters:
+// - dir: The slash separated directory relative to the template root.
+// Returns: The items found in the directory and an error if the directory does not exist.
+func (m *MemorySource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	return m.index.readDir(dir)
+}
+
+// Open returns a reader over the in-memory contents of the file at the given path.
+// Parameters:
+// - file: The slash separated path of the file relative to the template root.
+// Returns: A reader over the file contents and an error if the file does not exist.
+func (m *MemorySource) Open(file string) (io.ReadCloser, error) {
+	content, ok := m.files[cleanPath(file)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: file, Err: fs.ErrNotExist}
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
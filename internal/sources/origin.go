@@ -0,0 +1,168 @@
+package sources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+// Origin identifies where a template was read from, e.g. to decide whether its hooks are trusted.
+type Origin struct {
+	// Source is the repository as owner/name, prefixed with the host for instances other than github.com,
+	// or the absolute path or URL of a local directory or archive.
+	Source string
+	// SHA is the commit the repository was read at, or the sha256 of the contents of a local directory or archive,
+	// prefixed with sha256:, so trusting a SHA never trusts contents that changed since.
+	SHA string
+}
+
+// String formats the origin as source@sha, or just the source when there is no SHA.
+func (o Origin) String() string {
+	if o.SHA == consts.EMPTY_STRING {
+		return o.Source
+	}
+	return fmt.Sprintf("%s@%s", o.Source, o.SHA)
+}
+
+// contentSHAPrefix prefixes content hashes, telling them apart from commit SHAs.
+const contentSHAPrefix = "sha256:"
+
+// HasContentSHA reports whether the SHA of the origin hashes the contents of a local directory or archive rather than naming a commit.
+// Contents that can change under the same source are only ever trusted at their exact hash.
+func (o Origin) HasContentSHA() bool {
+	return strings.HasPrefix(o.SHA, contentSHAPrefix)
+}
+
+// OriginSource is a TemplateSource that knows where it was read from.
+type OriginSource struct {
+	TemplateSource
+	origin Origin
+}
+
+// WithOrigin records where src was read from.
+// Parameters:
+// - src: The template source.
+// - origin: Where the source was read from.
+// Returns: A pointer to the OriginSource reading from src.
+func WithOrigin(src TemplateSource, origin Origin) *OriginSource {
+	return &OriginSource{TemplateSource: src, origin: origin}
+}
+
+// Origins lists where a template source was read from: one origin per layer of a layered source.
+// Sources whose origin is unknown are left out.
+// Parameters:
+// - src: The template source.
+// Returns: The origins of the source.
+func Origins(src TemplateSource) []Origin {
+	switch s := src.(type) {
+	case *OriginSource:
+		return []Origin{s.origin}
+	case *SubSource:
+		return Origins(s.src)
+	case *DeferredSource:
+		return Origins(s.source())
+	case *LayeredSource:
+		var origins []Origin
+		for _, layer := range s.layers {
+			origins = append(origins, Origins(layer.Source)...)
+		}
+		return origins
+	default:
+		return nil
+	}
+}
+
+// remoteOrigin builds the origin of a repository read at the given SHA from the selected provider.
+// Repositories on github.com are named owner/name, and those on any other instance host/owner/name.
+// Parameters:
+// - opts: CLI options of type types.CliFlags.
+// - sha: The commit the repository is read at.
+// Returns: The Origin of the repository.
+func remoteOrigin(opts types.CliFlags, sha string) Origin {
+	source := fmt.Sprintf("%s/%s", opts.RepoOwner, opts.RepoName)
+
+	apiUrl := opts.ApiUrl
+	if apiUrl == consts.EMPTY_STRING && opts.Provider == consts.PROVIDER_GITLAB {
+		apiUrl = consts.GITLAB_API_URL
+	}
+	if u, err := url.Parse(apiUrl); err == nil && u.Hostname() != consts.EMPTY_STRING && u.Hostname() != consts.GITHUB_API_HOST {
+		source = fmt.Sprintf("%s/%s", u.Hostname(), source)
+	}
+
+	return Origin{Source: source, SHA: sha}
+}
+
+// localOrigin builds the origin of a local directory or archive, using its absolute path unless it is a URL.
+// Parameters:
+// - location: The path or URL of the directory or archive.
+// - sha: The content hash of the directory or archive, see archiveSHA and treeSHA.
+// Returns: The Origin of the directory or archive.
+func localOrigin(location, sha string) Origin {
+	if !isRemote(location) {
+		if abs, err := filepath.Abs(location); err == nil {
+			location = abs
+		}
+	}
+	return Origin{Source: location, SHA: sha}
+}
+
+// archiveSHA hashes the bytes of an archive, so the hash can be checked with e.g. sha256sum.
+// Parameters:
+// - content: The bytes of the archive.
+// Returns: The hash as sha256:<hex>.
+func archiveSHA(content []byte) string {
+	sum := sha256.Sum256(content)
+	return contentSHAPrefix + hex.EncodeToString(sum[:])
+}
+
+// treeSHA hashes every file a template source serves, with its path, in a stable order.
+// Parameters:
+// - src: The template source, e.g. a LocalSource.
+// Returns: The hash as sha256:<hex> and an error if a directory or file could not be read.
+func treeSHA(src TemplateSource) (string, error) {
+	h := sha256.New()
+	if err := hashDir(h, src, consts.EMPTY_STRING); err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to hash template: %v", err)
+	}
+	return contentSHAPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashDir writes the path, size and contents of every file beneath dir to h.
+func hashDir(h hash.Hash, src TemplateSource, dir string) error {
+	items, err := src.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+
+	for _, item := range items {
+		if item.Type == consts.DIR_TYPE {
+			if err := hashDir(h, src, item.Path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		r, err := src.Open(item.Path)
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", item.Path, len(content))
+		h.Write(content)
+	}
+	return nil
+}
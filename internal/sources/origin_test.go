@@ -0,0 +1,94 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+func TestOrigins(t *testing.T) {
+	remote := WithOrigin(newMemorySource(), Origin{Source: "acme/templates", SHA: testSHA})
+	local := WithOrigin(newMemorySource(), Origin{Source: "/templates"})
+
+	assert.Equal(t, []Origin{{Source: "acme/templates", SHA: testSHA}}, Origins(remote))
+	assert.Equal(t, []Origin{{Source: "acme/templates", SHA: testSHA}}, Origins(Sub(remote, "go-cli")))
+	assert.Equal(t, []Origin{{Source: "acme/templates", SHA: testSHA}, {Source: "/templates"}},
+		Origins(NewLayeredSource(Layer{Name: "remote", Source: remote}, Layer{Name: "local", Source: local})))
+	assert.Nil(t, Origins(newMemorySource()))
+
+	assert.Equal(t, "acme/templates@"+testSHA, Origins(remote)[0].String())
+	assert.Equal(t, "/templates", Origins(local)[0].String())
+}
+
+func TestRemoteOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     types.CliFlags
+		expected string
+	}{
+		{"GitHub", types.CliFlags{RepoOwner: "acme", RepoName: "templates"}, "acme/templates"},
+		{"GitHub API", types.CliFlags{RepoOwner: "acme", RepoName: "templates", ApiUrl: consts.GITHUB_API_URL}, "acme/templates"},
+		{"GitHub Enterprise", types.CliFlags{RepoOwner: "acme", RepoName: "templates", ApiUrl: "https://ghe.example.com/api/v3"}, "ghe.example.com/acme/templates"},
+		{"GitLab", types.CliFlags{RepoOwner: "group/sub", RepoName: "templates", Provider: consts.PROVIDER_GITLAB}, "gitlab.com/group/sub/templates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Origin{Source: tt.expected, SHA: testSHA}, remoteOrigin(tt.opts, testSHA))
+		})
+	}
+}
+
+func TestLocalOrigin(t *testing.T) {
+	abs, _ := filepath.Abs("templates")
+	assert.Equal(t, Origin{Source: abs, SHA: "sha256:1"}, localOrigin("templates", "sha256:1"))
+	assert.Equal(t, Origin{Source: "https://example.com/t.zip", SHA: "sha256:2"}, localOrigin("https://example.com/t.zip", "sha256:2"))
+}
+
+func TestArchiveSHA(t *testing.T) {
+	// sha256sum of "hello"
+	assert.Equal(t, "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", archiveSHA([]byte("hello")))
+}
+
+func TestTreeSHA(t *testing.T) {
+	files := map[string]string{"README.md": "readme", ".licenseFiles/mit/LICENSE": "MIT"}
+	sha, err := treeSHA(NewMemorySource(files))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sha, "sha256:"))
+
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	local, err := NewLocalSource(dir)
+	require.NoError(t, err)
+	localSHA, err := treeSHA(local)
+	require.NoError(t, err)
+	assert.Equal(t, sha, localSHA, "the same files hash the same wherever they are read from")
+
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"Changed content", map[string]string{"README.md": "changed", ".licenseFiles/mit/LICENSE": "MIT"}},
+		{"Renamed file", map[string]string{"README.txt": "readme", ".licenseFiles/mit/LICENSE": "MIT"}},
+		{"Added file", map[string]string{"README.md": "readme", ".licenseFiles/mit/LICENSE": "MIT", "hook.sh": ""}},
+		{"Content moved between files", map[string]string{"README.md": "readmeMIT", ".licenseFiles/mit/LICENSE": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := treeSHA(NewMemorySource(tt.files))
+			require.NoError(t, err)
+			assert.NotEqual(t, sha, changed)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package sources
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github-project-template/internal/consts"
+)
+
+// Reference identifies a template repository, the ref to read it at and the subdirectory used as the template root.
+type Reference struct {
+	// Owner is the owner of the repository, which may contain slashes for nested GitLab groups.
+	Owner string
+	// Name is the name of the repository.
+	Name string
+	// Ref is the branch, tag or commit to read; empty when none was given.
+	Ref string
+	// Subdir is the slash separated directory used as the template root; empty for the repository root.
+	Subdir string
+}
+
+// String formats the reference using the owner/name@ref//subdir syntax.
+func (r Reference) String() string {
+	s := fmt.Sprintf("%s/%s", r.Owner, r.Name)
+	if r.Ref != consts.EMPTY_STRING {
+		s = fmt.Sprintf("%s@%s", s, r.Ref)
+	}
+	if r.Subdir != consts.EMPTY_STRING {
+		s = fmt.Sprintf("%s//%s", s, r.Subdir)
+	}
+	return s
+}
+
+// ParseReference parses a template reference of the form owner/name[@ref][//subdir], e.g. acme/templates@v2.1.0//go-cli.
+// Pasted repository URLs are accepted as well, such as https://github.com/owner/name/tree/branch/path,
+// https://gitlab.com/group/name/-/tree/branch/path or https://gitea.example.com/owner/name/src/branch/main/path.
+// Parameters:
+// - s: The reference to parse.
+// Returns: The parsed Reference and an error if s does not name a repository.
+func ParseReference(s string) (Reference, error) {
+	if isRemote(s) {
+		return parseReferenceUrl(s)
+	}
+
+	var ref Reference
+	repo, subdir, _ := strings.Cut(s, "//")
+	ref.Subdir = cleanPath(subdir)
+
+	if at := strings.LastIndex(repo, "@"); at >= 0 {
+		repo, ref.Ref = repo[:at], repo[at+1:]
+	}
+
+	slash := strings.LastIndex(repo, "/")
+	if slash <= 0 || slash == len(repo)-1 || strings.HasPrefix(repo, ".") || strings.HasPrefix(repo, "/") {
+		return Reference{}, fmt.Errorf("invalid template reference %s, expected owner/name[@ref][//subdir]", s)
+	}
+	ref.Owner, ref.Name = repo[:slash], repo[slash+1:]
+
+	return ref, nil
+}
+
+// parseReferenceUrl parses a pasted GitHub, GitLab, Gitea or Forgejo repository URL, optionally pointing at a directory on a branch.
+func parseReferenceUrl(s string) (Reference, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Reference{}, fmt.Errorf("invalid template url %s: %v", s, err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	repoParts, ref, subdir := parts, consts.EMPTY_STRING, []string(nil)
+
+	switch {
+	case indexOf(parts, "-") >= 2:
+		// GitLab: /group/name/-/tree/<ref>/<path>
+		idx := indexOf(parts, "-")
+		repoParts = parts[:idx]
+		if rest := parts[idx+1:]; len(rest) >= 2 && (rest[0] == "tree" || rest[0] == "blob") {
+			ref, subdir = rest[1], rest[2:]
+		}
+	case len(parts) >= 4 && (parts[2] == "tree" || parts[2] == "blob"):
+		// GitHub: /owner/name/tree/<ref>/<path>
+		repoParts, ref, subdir = parts[:2], parts[3], parts[4:]
+	case len(parts) >= 5 && parts[2] == "src" && (parts[3] == "branch" || parts[3] == "tag" || parts[3] == "commit"):
+		// Gitea and Forgejo: /owner/name/src/branch/<ref>/<path>
+		repoParts, ref, subdir = parts[:2], parts[4], parts[5:]
+	}
+
+	if len(repoParts) < 2 || repoParts[0] == consts.EMPTY_STRING {
+		return Reference{}, fmt.Errorf("invalid template url %s, expected a repository url", s)
+	}
+
+	return Reference{
+		Owner:  strings.Join(repoParts[:len(repoParts)-1], "/"),
+		Name:   strings.TrimSuffix(repoParts[len(repoParts)-1], ".git"),
+		Ref:    ref,
+		Subdir: cleanPath(path.Join(subdir...)),
+	}, nil
+}
+
+// indexOf returns the index of the first element of parts equal to s, or -1.
+func indexOf(parts []string, s string) int {
+	for i, part := range parts {
+		if part == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,83 @@
+package sources
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    Reference
+		expectedErr bool
+	}{
+		{"Owner and name", "acme/templates", Reference{Owner: "acme", Name: "templates"}, false},
+		{"With ref", "acme/templates@v2.1.0", Reference{Owner: "acme", Name: "templates", Ref: "v2.1.0"}, false},
+		{"With ref and subdir", "acme/templates@v2.1.0//go-cli", Reference{Owner: "acme", Name: "templates", Ref: "v2.1.0", Subdir: "go-cli"}, false},
+		{"With subdir only", "acme/templates//langs/go/", Reference{Owner: "acme", Name: "templates", Subdir: "langs/go"}, false},
+		{"Nested group", "group/sub/templates@main", Reference{Owner: "group/sub", Name: "templates", Ref: "main"}, false},
+		{"GitHub repository url", "https://github.com/acme/templates.git", Reference{Owner: "acme", Name: "templates"}, false},
+		{"GitHub tree url", "https://github.com/acme/templates/tree/main/go-cli/base", Reference{Owner: "acme", Name: "templates", Ref: "main", Subdir: "go-cli/base"}, false},
+		{"GitLab tree url", "https://gitlab.com/group/sub/templates/-/tree/dev/go-cli", Reference{Owner: "group/sub", Name: "templates", Ref: "dev", Subdir: "go-cli"}, false},
+		{"Gitea tree url", "https://gitea.example.com/acme/templates/src/branch/main/go-cli", Reference{Owner: "acme", Name: "templates", Ref: "main", Subdir: "go-cli"}, false},
+		{"Missing name", "acme/", Reference{}, true},
+		{"Missing owner", "templates", Reference{}, true},
+		{"Relative path", "./templates/go", Reference{}, true},
+		{"Absolute path", "/tmp/templates", Reference{}, true},
+		{"Url without repository", "https://github.com/acme", Reference{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseReference(tt.input)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ref)
+		})
+	}
+}
+
+func TestReferenceString(t *testing.T) {
+	ref := Reference{Owner: "acme", Name: "templates", Ref: "v2", Subdir: "go-cli"}
+	assert.Equal(t, "acme/templates@v2//go-cli", ref.String())
+	assert.Equal(t, "acme/templates", Reference{Owner: "acme", Name: "templates"}.String())
+}
+
+func TestSubSource(t *testing.T) {
+	src := Sub(NewMemorySource(map[string]string{
+		"go-cli/.licenseFiles/mit/LICENSE": "MIT",
+		"go-cli/README.md":                 "readme",
+		"python/README.md":                 "python readme",
+	}), "go-cli")
+
+	items, err := src.ReadDir(consts.EMPTY_STRING)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.TemplateItem{
+		{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+		{Type: consts.FILE_TYPE, Name: "README.md", Path: "README.md"},
+	}, items)
+
+	items, err = src.ReadDir(".licenseFiles/mit")
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{{Type: consts.FILE_TYPE, Name: "LICENSE", Path: ".licenseFiles/mit/LICENSE"}}, items)
+
+	rc, err := src.Open(".licenseFiles/mit/LICENSE")
+	require.NoError(t, err)
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", string(content))
+
+	root := NewMemorySource(nil)
+	assert.Same(t, root, Sub(root, "/"))
+}
@@ -0,0 +1,77 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+
+	"github-project-template/internal/httpclient"
+)
+
+// doRequest performs a GET request against url with the given headers using the shared HTTP client.
+// The caller is responsible for closing the returned body.
+// Parameters:
+// - url: The URL to request.
+// - headers: Additional request headers, e.g. an Accept header selecting a media type.
+// Returns: The response and an error if the request fails or does not return HTTP 200, wrapping fs.ErrNotExist for HTTP 404.
+func doRequest(url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpclient.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("request to %s failed with status: %v: %w", url, resp.Status, fs.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("request to %s failed with status: %v", url, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// getJSON performs a GET request against url and decodes the JSON response into v.
+// Parameters:
+// - url: The URL to request.
+// - v: A pointer to the value the response is decoded into.
+// Returns: An error if the request or decoding fails.
+func getJSON(url string, v interface{}) error {
+	_, err := getJSONWithHeaders(url, v)
+	return err
+}
+
+// getJSONWithHeaders performs a GET request against url, decodes the JSON response into v and returns the response headers,
+// which providers use for pagination.
+// Parameters:
+// - url: The URL to request.
+// - v: A pointer to the value the response is decoded into.
+// Returns: The response headers and an error if the request or decoding fails.
+func getJSONWithHeaders(url string, v interface{}) (http.Header, error) {
+	resp, err := doRequest(url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("failed to decode response %s: %v", url, err)
+	}
+	return resp.Header, nil
+}
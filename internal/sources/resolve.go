@@ -0,0 +1,277 @@
+package sources
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+// gitHubPageSize is the number of entries requested per page from GitHub list endpoints, the maximum GitHub allows.
+const gitHubPageSize = 100
+
+// commitSHAPattern matches a full, lowercase hexadecimal Git commit SHA.
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// RefResolver resolves refs of a template repository to immutable commit SHAs.
+type RefResolver interface {
+	// DefaultBranch returns the name of the default branch of the repository.
+	DefaultBranch() (string, error)
+	// CommitSHA returns the SHA of the commit the branch, tag or (short) commit SHA ref points to.
+	CommitSHA(ref string) (string, error)
+	// Tags lists every tag of the repository, following all pages of results.
+	Tags() ([]types.Tag, error)
+}
+
+// ResolvedRef is a ref pinned to the commit it pointed to when it was resolved.
+type ResolvedRef struct {
+	// Ref is the branch, tag or commit that was resolved, e.g. the highest tag matching a semver range.
+	Ref string
+	// SHA is the full commit SHA every request is made against.
+	SHA string
+}
+
+// ResolveRef resolves ref to the commit SHA it currently points to, using the default branch of the repository when ref is empty.
+// A ref starting with a semver operator, such as ^2.1, ~1.4 or >=1.0 <2.0, selects the highest tag satisfying the range.
+// Every later request is made against the returned SHA, so a stub never mixes files from two commits when the branch moves mid-run.
+// Parameters:
+// - resolver: The RefResolver of the template repository.
+// - ref: The branch, tag, commit SHA or semver range to resolve; empty for the default branch.
+// Returns: The ResolvedRef and an error if the ref could not be resolved.
+func ResolveRef(resolver RefResolver, ref string) (ResolvedRef, error) {
+	if commitSHAPattern.MatchString(ref) {
+		return ResolvedRef{Ref: ref, SHA: ref}, nil
+	}
+
+	if isVersionRange(ref) {
+		return resolveVersionRange(resolver, ref)
+	}
+
+	if ref == consts.EMPTY_STRING {
+		branch, err := resolver.DefaultBranch()
+		if err != nil {
+			return ResolvedRef{}, fmt.Errorf("failed to look up the default branch: %v", err)
+		}
+		ref = branch
+	}
+
+	sha, err := resolver.CommitSHA(ref)
+	if err != nil {
+		return ResolvedRef{}, fmt.Errorf("failed to resolve ref %s: %v", ref, err)
+	}
+	if sha == consts.EMPTY_STRING {
+		return ResolvedRef{}, fmt.Errorf("failed to resolve ref %s: no commit found", ref)
+	}
+	return ResolvedRef{Ref: ref, SHA: sha}, nil
+}
+
+// isVersionRange reports whether ref is a semver range rather than a branch, tag or commit, based on a leading range operator or wildcard.
+func isVersionRange(ref string) bool {
+	return ref != consts.EMPTY_STRING && (strings.ContainsAny(ref[:1], "^~<>=*") || strings.Contains(ref, "||"))
+}
+
+// resolveVersionRange resolves the highest tag satisfying the semver range to its commit.
+// Tags that are not valid semantic versions, e.g. latest, are ignored, and pre-releases only match ranges that include a pre-release.
+// Parameters:
+// - resolver: The RefResolver of the template repository.
+// - ref: The semver range, e.g. ^2.1.
+// Returns: The ResolvedRef of the matching tag and an error if no tag satisfies the range.
+func resolveVersionRange(resolver RefResolver, ref string) (ResolvedRef, error) {
+	constraint, err := semver.NewConstraint(ref)
+	if err != nil {
+		return ResolvedRef{}, fmt.Errorf("invalid version range %s: %v", ref, err)
+	}
+
+	tags, err := resolver.Tags()
+	if err != nil {
+		return ResolvedRef{}, fmt.Errorf("failed to list tags: %v", err)
+	}
+
+	var (
+		best    *semver.Version
+		bestTag types.Tag
+	)
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag.Name)
+		if err != nil || !constraint.Check(version) {
+			continue
+		}
+		if best == nil || version.GreaterThan(best) {
+			best, bestTag = version, tag
+		}
+	}
+
+	if best == nil {
+		return ResolvedRef{}, fmt.Errorf("no tag satisfies version range %s", ref)
+	}
+
+	if sha := tagSHA(bestTag); commitSHAPattern.MatchString(sha) {
+		return ResolvedRef{Ref: bestTag.Name, SHA: sha}, nil
+	}
+	return ResolveRef(resolver, bestTag.Name)
+}
+
+// tagSHA returns the commit SHA a tag listing entry points to, as reported by any provider.
+func tagSHA(tag types.Tag) string {
+	if tag.Commit.SHA != consts.EMPTY_STRING {
+		return tag.Commit.SHA
+	}
+	return tag.Commit.ID
+}
+
+// newRefResolver creates the RefResolver for the repository described by the options on the selected provider,
+// initializing the shared HTTP client with the provider's authentication if needed.
+// Parameters:
+// - opts: CLI options of type types.CliFlags.
+// Returns: The RefResolver and an error if the provider is unknown or the HTTP client could not be initialized.
+func newRefResolver(opts types.CliFlags) (RefResolver, error) {
+	switch opts.Provider {
+	case consts.PROVIDER_GITHUB, consts.EMPTY_STRING:
+		repoUrl := gitHubRepoUrl(opts.ApiUrl, opts.RepoOwner, opts.RepoName)
+		if err := ensureClient(opts.GithubToken, httpclient.AuthorizationHeader, repoUrl); err != nil {
+			return nil, err
+		}
+		return &gitHubRefs{repoUrl: repoUrl}, nil
+	case consts.PROVIDER_GITLAB:
+		apiUrl := gitLabApiUrl(opts.ApiUrl)
+		if err := ensureClient(opts.GithubToken, httpclient.PrivateTokenHeader, apiUrl); err != nil {
+			return nil, err
+		}
+		return &gitLabRefs{projectUrl: gitLabProjectUrl(apiUrl, fmt.Sprintf("%s/%s", opts.RepoOwner, opts.RepoName))}, nil
+	case consts.PROVIDER_GITEA, consts.PROVIDER_FORGEJO:
+		if opts.ApiUrl == consts.EMPTY_STRING {
+			return nil, fmt.Errorf("provider %s requires an API URL", opts.Provider)
+		}
+		if err := ensureClient(opts.GithubToken, httpclient.AuthorizationHeader, opts.ApiUrl); err != nil {
+			return nil, err
+		}
+		return &giteaRefs{repoUrl: giteaRepoUrl(opts.ApiUrl, opts.RepoOwner, opts.RepoName)}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider: %s", opts.Provider)
+	}
+}
+
+// gitHubRefs resolves refs through the GitHub repository and commits endpoints.
+type gitHubRefs struct {
+	// repoUrl is the API URL of the repository, e.g. https://api.github.com/repos/owner/name.
+	repoUrl string
+}
+
+// DefaultBranch returns the default branch reported by GET /repos/{owner}/{name}.
+func (g *gitHubRefs) DefaultBranch() (string, error) {
+	var repo types.Repository
+	if err := getJSON(g.repoUrl, &repo); err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	return repo.DefaultBranch, nil
+}
+
+// CommitSHA returns the SHA reported by GET /repos/{owner}/{name}/commits/{ref}.
+func (g *gitHubRefs) CommitSHA(ref string) (string, error) {
+	var commit types.Commit
+	if err := getJSON(fmt.Sprintf("%s/commits/%s", g.repoUrl, ref), &commit); err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	return commit.SHA, nil
+}
+
+// Tags lists the tags reported by GET /repos/{owner}/{name}/tags, following the Link header through every page.
+func (g *gitHubRefs) Tags() ([]types.Tag, error) {
+	return listLinkedTags(fmt.Sprintf("%s/tags?per_page=%d", g.repoUrl, gitHubPageSize))
+}
+
+// gitLabRefs resolves refs through the GitLab project and repository commits endpoints.
+type gitLabRefs struct {
+	// projectUrl is the API URL of the project, e.g. https://gitlab.com/api/v4/projects/owner%2Fname.
+	projectUrl string
+}
+
+// DefaultBranch returns the default branch reported by GET /projects/{id}.
+func (g *gitLabRefs) DefaultBranch() (string, error) {
+	var project types.Repository
+	if err := getJSON(g.projectUrl, &project); err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	return project.DefaultBranch, nil
+}
+
+// CommitSHA returns the id reported by GET /projects/{id}/repository/commits/{ref}.
+func (g *gitLabRefs) CommitSHA(ref string) (string, error) {
+	var commit types.Commit
+	if err := getJSON(fmt.Sprintf("%s/repository/commits/%s", g.projectUrl, url.PathEscape(ref)), &commit); err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	return commit.ID, nil
+}
+
+// Tags lists the tags reported by GET /projects/{id}/repository/tags, following the X-Next-Page header through every page.
+func (g *gitLabRefs) Tags() ([]types.Tag, error) {
+	var tags []types.Tag
+
+	for page := "1"; page != consts.EMPTY_STRING; {
+		var pageTags []types.Tag
+		header, err := getJSONWithHeaders(fmt.Sprintf("%s/repository/tags?per_page=%d&page=%s", g.projectUrl, gitLabPageSize, page), &pageTags)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, pageTags...)
+		page = header.Get("X-Next-Page")
+	}
+
+	return tags, nil
+}
+
+// giteaRefs resolves refs through the Gitea and Forgejo repository and git commits endpoints.
+type giteaRefs struct {
+	// repoUrl is the API URL of the repository, e.g. https://gitea.example.com/api/v1/repos/owner/name.
+	repoUrl string
+}
+
+// DefaultBranch returns the default branch reported by GET /repos/{owner}/{name}.
+func (g *giteaRefs) DefaultBranch() (string, error) {
+	var repo types.Repository
+	if err := getJSON(g.repoUrl, &repo); err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	return repo.DefaultBranch, nil
+}
+
+// CommitSHA returns the SHA reported by GET /repos/{owner}/{name}/git/commits/{ref}, which accepts branches, tags and SHAs.
+func (g *giteaRefs) CommitSHA(ref string) (string, error) {
+	var commit types.Commit
+	if err := getJSON(fmt.Sprintf("%s/git/commits/%s", g.repoUrl, url.PathEscape(ref)), &commit); err != nil {
+		return consts.EMPTY_STRING, err
+	}
+	return commit.SHA, nil
+}
+
+// Tags lists the tags reported by GET /repos/{owner}/{name}/tags, following the Link header through every page.
+func (g *giteaRefs) Tags() ([]types.Tag, error) {
+	return listLinkedTags(fmt.Sprintf("%s/tags?page=1&limit=%d", g.repoUrl, giteaPageSize))
+}
+
+// listLinkedTags lists the tags starting at the first page URL, following rel="next" Link headers as sent by GitHub, Gitea and Forgejo.
+// Parameters:
+// - first: The URL of the first page of the tag listing.
+// Returns: The tags of every page and an error if a request fails.
+func listLinkedTags(first string) ([]types.Tag, error) {
+	var tags []types.Tag
+
+	for next := first; next != consts.EMPTY_STRING; {
+		var pageTags []types.Tag
+		header, err := getJSONWithHeaders(next, &pageTags)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, pageTags...)
+		next = nextPageUrl(header)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,201 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+const testSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func TestResolveRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		ref         string
+		expectedErr bool
+	}{
+		{"GitHub default branch", consts.PROVIDER_GITHUB, consts.EMPTY_STRING, false},
+		{"GitHub tag", consts.PROVIDER_GITHUB, "v1.0.0", false},
+		{"GitHub full SHA", consts.PROVIDER_GITHUB, testSHA, false},
+		{"GitHub unknown ref", consts.PROVIDER_GITHUB, "missing", true},
+		{"GitLab default branch", consts.PROVIDER_GITLAB, consts.EMPTY_STRING, false},
+		{"GitLab branch with slash", consts.PROVIDER_GITLAB, "feature/x", false},
+		{"Gitea default branch", consts.PROVIDER_GITEA, consts.EMPTY_STRING, false},
+		{"Forgejo short SHA", consts.PROVIDER_FORGEJO, "0123456", false},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/repos/owner/repo", "/api/v4/projects/owner%2Frepo", "/api/v1/repos/owner/repo":
+			json.NewEncoder(w).Encode(types.Repository{DefaultBranch: "main"})
+		case "/repos/owner/repo/commits/main", "/repos/owner/repo/commits/v1.0.0",
+			"/api/v1/repos/owner/repo/git/commits/main", "/api/v1/repos/owner/repo/git/commits/0123456":
+			json.NewEncoder(w).Encode(types.Commit{SHA: testSHA})
+		case "/api/v4/projects/owner%2Frepo/repository/commits/main", "/api/v4/projects/owner%2Frepo/repository/commits/feature%2Fx":
+			json.NewEncoder(w).Encode(types.Commit{ID: testSHA})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	httpclient.Client = server.Client()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := newRefResolver(types.CliFlags{Provider: tt.provider, ApiUrl: server.URL, RepoOwner: "owner", RepoName: "repo"})
+			require.NoError(t, err)
+
+			resolved, err := ResolveRef(resolver, tt.ref)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testSHA, resolved.SHA)
+		})
+	}
+}
+
+func TestResolveVersionRange(t *testing.T) {
+	tags := map[string][]types.Tag{
+		"1": {
+			{Name: "latest", Commit: types.Commit{SHA: "l"}},
+			{Name: "v2.3.0-rc.1", Commit: types.Commit{SHA: "rc"}},
+			{Name: "v2.2.1", Commit: types.Commit{SHA: "221"}},
+			{Name: "v2.1.0", Commit: types.Commit{SHA: "210"}},
+		},
+		"2": {
+			{Name: "v1.5.0", Commit: types.Commit{SHA: "150"}},
+			{Name: "v1.4.7", Commit: types.Commit{SHA: "147"}},
+			{Name: "v1.4.2", Commit: types.Commit{SHA: "142"}},
+			{Name: "v3.0.0", Commit: types.Commit{SHA: "300"}},
+		},
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/tags":
+			page := r.URL.Query().Get("page")
+			if page == consts.EMPTY_STRING {
+				page = "1"
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/tags?per_page=100&page=2>; rel="next"`, server.URL))
+			}
+			json.NewEncoder(w).Encode(tags[page])
+		case "/repos/owner/repo/commits/v2.2.1", "/repos/owner/repo/commits/v1.4.7", "/repos/owner/repo/commits/v3.0.0":
+			json.NewEncoder(w).Encode(types.Commit{SHA: testSHA})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	httpclient.Client = server.Client()
+
+	resolver, err := newRefResolver(types.CliFlags{ApiUrl: server.URL, RepoOwner: "owner", RepoName: "repo"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		ref         string
+		expectedTag string
+		expectedErr bool
+	}{
+		{"Caret range", "^2.1", "v2.2.1", false},
+		{"Tilde range", "~1.4", "v1.4.7", false},
+		{"Comparison range", ">=1.0", "v3.0.0", false},
+		{"No matching tag", "^4.0", consts.EMPTY_STRING, true},
+		{"Invalid range", "^two", consts.EMPTY_STRING, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := ResolveRef(resolver, tt.ref)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, ResolvedRef{Ref: tt.expectedTag, SHA: testSHA}, resolved)
+		})
+	}
+}
+
+func TestIsVersionRange(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected bool
+	}{
+		{"^2.1", true},
+		{"~1.4", true},
+		{">=1.0 <2.0", true},
+		{"1.x || 2.x", true},
+		{"v2.1.0", false},
+		{"main", false},
+		{consts.EMPTY_STRING, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isVersionRange(tt.ref))
+		})
+	}
+}
+
+func TestNewRefResolverErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts types.CliFlags
+	}{
+		{"Unknown provider", types.CliFlags{Provider: "bitbucket"}},
+		{"Gitea without API URL", types.CliFlags{Provider: consts.PROVIDER_GITEA}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := newRefResolver(tt.opts)
+			assert.Error(t, err)
+			assert.Nil(t, resolver)
+		})
+	}
+}
+
+func TestCheckFetchMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		fetchMode   string
+		expectedErr bool
+	}{
+		{"GitHub tree", consts.PROVIDER_GITHUB, consts.FETCH_TREE, false},
+		{"GitLab contents", consts.PROVIDER_GITLAB, consts.FETCH_CONTENTS, false},
+		{"GitLab tarball", consts.PROVIDER_GITLAB, consts.FETCH_TARBALL, true},
+		{"Unknown mode", consts.PROVIDER_GITHUB, "clone", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFetchMode(types.CliFlags{Provider: tt.provider, FetchMode: tt.fetchMode})
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
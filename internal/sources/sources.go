@@ -0,0 +1,261 @@
+package sources
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+// TemplateSource abstracts where template files are read from, so the repository handlers
+// do not need to know whether a template lives on GitHub or on the local filesystem.
+type TemplateSource interface {
+	// ReadDir lists the items directly beneath path, where an empty path is the template root.
+	ReadDir(path string) ([]types.TemplateItem, error)
+	// Open returns the contents of the file at path. The caller is responsible for closing it.
+	Open(path string) (io.ReadCloser, error)
+}
+
+// New creates the TemplateSource described by the CLI options.
+// opts.Template and every entry of opts.Sources name a template bundle archive (a local path or URL ending in .zip, .tar.gz or .tgz),
+// a local template directory or a repository reference on the selected provider, see ParseReference. The template is the lowest layer,
+// and several sources are merged into a LayeredSource where later sources override earlier ones.
+// Without any, the repository described by the owner, name and branch is used.
+// Local directories are only hashed, and tarballs only downloaded, by Fetch, so the manifest can be loaded and validated first.
+// Parameters:
+// - opts: CLI options of type types.CliFlags.
+// Returns: The TemplateSource to read the template from and an error if it could not be created.
+func New(opts types.CliFlags) (TemplateSource, error) {
+	specs := opts.Sources
+	if opts.Template != consts.EMPTY_STRING {
+		specs = append([]string{opts.Template}, specs...)
+	}
+
+	switch len(specs) {
+	case 0:
+		return newRemote(opts)
+	case 1:
+		return newSource(specs[0], opts)
+	}
+
+	layers := make([]Layer, 0, len(specs))
+	for _, spec := range specs {
+		src, err := newSource(spec, opts)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, Layer{Name: spec, Source: src})
+	}
+
+	return NewLayeredSource(layers...), nil
+}
+
+// newSource creates the TemplateSource for a single --source value.
+// Parameters:
+// - spec: An archive path or URL, a local directory, or a repository reference such as owner/name@ref//subdir.
+// - opts: CLI options of type types.CliFlags, supplying the provider settings for repositories.
+// Returns: The TemplateSource and an error if spec does not name a usable source.
+func newSource(spec string, opts types.CliFlags) (TemplateSource, error) {
+	if IsArchive(spec) {
+		content, err := readArchive(spec)
+		if err != nil {
+			return nil, err
+		}
+		src, err := loadArchive(spec, content)
+		if err != nil {
+			return nil, err
+		}
+		return WithOrigin(src, localOrigin(spec, archiveSHA(content))), nil
+	}
+
+	if isLocalDir(spec) {
+		src, err := NewLocalSource(spec)
+		if err != nil {
+			return nil, err
+		}
+		// hashing reads every file, so it waits until the variables are validated
+		return Defer(src, func() (TemplateSource, error) {
+			sha, err := treeSHA(src)
+			if err != nil {
+				return nil, err
+			}
+			return WithOrigin(src, localOrigin(spec, sha)), nil
+		}), nil
+	}
+
+	ref, err := ParseReference(spec)
+	if err != nil {
+		return nil, fmt.Errorf("template source %s is neither a local directory, an archive nor a repository reference", spec)
+	}
+
+	opts.RepoOwner, opts.RepoName = ref.Owner, ref.Name
+	if ref.Ref != consts.EMPTY_STRING {
+		opts.BranchName = ref.Ref
+	}
+
+	src, err := newRemote(opts)
+	if err != nil {
+		return nil, err
+	}
+	return Sub(src, ref.Subdir), nil
+}
+
+// ReferenceOwner returns the owner of the repository the template is read from: the owner of the template reference,
+// or else of the first source naming a repository. Archives and local directories have no owner.
+// Parameters:
+// - opts: CLI options of type types.CliFlags.
+// Returns: The owner, or empty when neither the template nor any source names a repository.
+func ReferenceOwner(opts types.CliFlags) string {
+	specs := opts.Sources
+	if opts.Template != consts.EMPTY_STRING {
+		specs = append([]string{opts.Template}, specs...)
+	}
+
+	for _, spec := range specs {
+		if IsArchive(spec) || isLocalDir(spec) {
+			continue
+		}
+		if ref, err := ParseReference(spec); err == nil {
+			return ref.Owner
+		}
+	}
+	return consts.EMPTY_STRING
+}
+
+// isLocalDir reports whether spec names an existing local directory.
+func isLocalDir(spec string) bool {
+	info, err := os.Stat(spec)
+	return err == nil && info.IsDir()
+}
+
+// newRemote creates the TemplateSource for the repository described by the owner, name and branch on the selected provider.
+// The branch, tag, commit or semver range (or the default branch when none is given) is resolved to a commit SHA first and reported,
+// and every request is made against that SHA, which is recorded as the origin of the source.
+// Parameters:
+// - opts: CLI options of type types.CliFlags.
+// Returns: The TemplateSource and an error if it could not be created.
+func newRemote(opts types.CliFlags) (TemplateSource, error) {
+	if err := checkFetchMode(opts); err != nil {
+		return nil, err
+	}
+
+	resolver, err := newRefResolver(opts)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := ResolveRef(resolver, opts.BranchName)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Using %s/%s@%s (%s)\n", opts.RepoOwner, opts.RepoName, color.New(color.FgCyan).Sprint(resolved.Ref), resolved.SHA)
+	opts.BranchName = resolved.SHA
+
+	var src TemplateSource
+	switch opts.Provider {
+	case consts.PROVIDER_GITLAB:
+		src, err = NewGitLabSource(opts.ApiUrl, fmt.Sprintf("%s/%s", opts.RepoOwner, opts.RepoName), opts.BranchName, opts.GithubToken)
+	case consts.PROVIDER_GITEA, consts.PROVIDER_FORGEJO:
+		src, err = NewGiteaSource(opts.ApiUrl, opts.RepoOwner, opts.RepoName, opts.BranchName, opts.GithubToken)
+	default:
+		src, err = newGitHub(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return WithOrigin(src, remoteOrigin(opts, resolved.SHA)), nil
+}
+
+// checkFetchMode validates the fetch mode against the selected provider, where only GitHub supports modes other than contents.
+// Parameters:
+// - opts: CLI options of type types.CliFlags.
+// Returns: An error if the fetch mode is unknown or not supported by the provider.
+func checkFetchMode(opts types.CliFlags) error {
+	switch opts.FetchMode {
+	case consts.FETCH_CONTENTS, consts.EMPTY_STRING:
+		return nil
+	case consts.FETCH_TARBALL, consts.FETCH_TREE:
+		if opts.Provider != consts.PROVIDER_GITHUB && opts.Provider != consts.EMPTY_STRING {
+			return fmt.Errorf("fetch mode %s is not supported by provider %s", opts.FetchMode, opts.Provider)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown fetch mode: %s", opts.FetchMode)
+	}
+}
+
+// newGitHub creates the GitHub TemplateSource for the requested fetch mode.
+// opts.ApiUrl selects a GitHub Enterprise Server instance, e.g. https://ghe.example.com/api/v3.
+// Parameters:
+// - opts: CLI options of type types.CliFlags.
+// Returns: The TemplateSource to read the template from and an error if it could not be created.
+func newGitHub(opts types.CliFlags) (TemplateSource, error) {
+	repoUrl := gitHubRepoUrl(opts.ApiUrl, opts.RepoOwner, opts.RepoName)
+
+	switch opts.FetchMode {
+	case consts.FETCH_TARBALL:
+		// the manifest is read through the Contents API, so the tarball is only downloaded once the variables are validated
+		light, err := NewGitHubSource(fmt.Sprintf("%s/contents", repoUrl), opts.BranchName, opts.GithubToken)
+		if err != nil {
+			return nil, err
+		}
+		return Defer(light, func() (TemplateSource, error) {
+			return NewTarballSource(fmt.Sprintf("%s/tarball/%s", repoUrl, opts.BranchName), opts.GithubToken)
+		}), nil
+	case consts.FETCH_TREE:
+		return NewTreeSource(repoUrl, opts.BranchName, opts.GithubToken)
+	default:
+		return NewGitHubSource(fmt.Sprintf("%s/contents", repoUrl), opts.BranchName, opts.GithubToken)
+	}
+}
+
+// gitHubRepoUrl builds the API URL of a GitHub repository, using github.com when apiUrl is empty.
+func gitHubRepoUrl(apiUrl, owner, name string) string {
+	apiUrl = strings.TrimSuffix(apiUrl, "/")
+	if apiUrl == consts.EMPTY_STRING {
+		apiUrl = consts.GITHUB_API_URL
+	}
+	return fmt.Sprintf("%s/repos/%s/%s", apiUrl, owner, name)
+}
+
+// ensureClient initializes the shared HTTP client with the given token if it has not been initialized yet.
+// The token is scoped to the hosts serving apiUrl, see authHosts.
+// Parameters:
+// - token: The authentication token used for requests.
+// - header: The header the token is sent in.
+// - apiUrl: The API URL of the provider the token belongs to.
+// Returns: An error if the HTTP client could not be initialized.
+func ensureClient(token string, header httpclient.AuthHeader, apiUrl string) error {
+	if httpclient.Client != nil {
+		return nil
+	}
+
+	if err := httpclient.InitClientWithHeader(token, header, authHosts(apiUrl)...); err != nil {
+		return fmt.Errorf("failed to initialize HTTP client: %v", err)
+	}
+	return nil
+}
+
+// authHosts returns the hosts a token for apiUrl may be sent to.
+// For github.com these are the API, raw download and codeload hosts; for any other instance, such as GitHub Enterprise Server,
+// the token is scoped to the API host, whose subdomains (e.g. raw.ghe.example.com) are allowed as well.
+// Parameters:
+// - apiUrl: The API URL of the provider.
+// Returns: The hosts the token is scoped to.
+func authHosts(apiUrl string) []string {
+	u, err := url.Parse(apiUrl)
+	if err != nil || u.Hostname() == consts.EMPTY_STRING {
+		return []string{apiUrl}
+	}
+
+	if u.Hostname() == consts.GITHUB_API_HOST {
+		return []string{consts.GITHUB_API_HOST, consts.GITHUB_RAW_HOST, consts.GITHUB_CODELOAD_HOST}
+	}
+	return []string{u.Hostname()}
+}
@@ -0,0 +1,51 @@
+package sources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+func TestAuthHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiUrl   string
+		expected []string
+	}{
+		{"github.com", "https://api.github.com", []string{"api.github.com", "raw.githubusercontent.com", "codeload.github.com"}},
+		{"GitHub Enterprise Server", "https://ghe.example.com/api/v3", []string{"ghe.example.com"}},
+		{"Instance with port", "https://gitlab.example.com:8443/api/v4", []string{"gitlab.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, authHosts(tt.apiUrl))
+		})
+	}
+}
+
+func TestReferenceOwner(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		opts     types.CliFlags
+		expected string
+	}{
+		{"Template reference", types.CliFlags{RepoOwner: "ondrovic", Template: "acme/templates@v2//go-cli"}, "acme"},
+		{"Template URL", types.CliFlags{Template: "https://github.com/acme/templates/tree/main/go-cli"}, "acme"},
+		{"Template before sources", types.CliFlags{Template: "acme/templates", Sources: []string{"team/overlay"}}, "acme"},
+		{"First repository source", types.CliFlags{Sources: []string{dir, "https://example.com/templates.zip", "team/overlay", "org/base"}}, "team"},
+		{"Local sources only", types.CliFlags{RepoOwner: "ondrovic", Sources: []string{dir, "templates.tar.gz"}}, consts.EMPTY_STRING},
+		{"Default repository", types.CliFlags{RepoOwner: "ondrovic"}, consts.EMPTY_STRING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ReferenceOwner(tt.opts))
+		})
+	}
+}
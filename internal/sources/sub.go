@@ -0,0 +1,54 @@
+package sources
+
+import (
+	"io"
+	"path"
+	"strings"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+// SubSource exposes a subdirectory of another TemplateSource as the template root,
+// so a single repository can hold several templates side by side.
+type SubSource struct {
+	src TemplateSource
+	dir string
+}
+
+// Sub returns a TemplateSource rooted at dir within src, or src itself when dir is the root.
+// Parameters:
+// - src: The source containing the template.
+// - dir: The slash separated directory used as the template root.
+// Returns: The TemplateSource rooted at dir.
+func Sub(src TemplateSource, dir string) TemplateSource {
+	dir = cleanPath(dir)
+	if dir == consts.EMPTY_STRING {
+		return src
+	}
+	return &SubSource{src: src, dir: dir}
+}
+
+// ReadDir lists the items directly beneath dir, relative to the subdirectory root.
+// Parameters:
+// - dir: The slash separated directory relative to the subdirectory root.
+// Returns: The items found in the directory, with paths relative to the subdirectory root, and an error if it could not be read.
+func (s *SubSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	items, err := s.src.ReadDir(path.Join(s.dir, dir))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].Path = strings.TrimPrefix(cleanPath(items[i].Path), s.dir+"/")
+	}
+	return items, nil
+}
+
+// Open opens the file at the given path relative to the subdirectory root.
+// Parameters:
+// - file: The slash separated path of the file relative to the subdirectory root.
+// Returns: The file contents and an error if it could not be opened.
+func (s *SubSource) Open(file string) (io.ReadCloser, error) {
+	return s.src.Open(path.Join(s.dir, file))
+}
@@ -0,0 +1,85 @@
+package sources
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/utils"
+)
+
+// NewTarballSource downloads the gzipped tarball at url with a single request and serves the template from memory.
+// The top level directory that GitHub wraps around the repository contents is stripped from every path.
+// Parameters:
+// - url: The tarball endpoint of the repository, e.g. https://api.github.com/repos/owner/name/tarball/master.
+// - token: The GitHub token used to authenticate requests.
+// Returns: A MemorySource holding the repository contents and an error if the download or extraction fails.
+func NewTarballSource(url, token string) (*MemorySource, error) {
+	if err := ensureClient(token, httpclient.AuthorizationHeader, url); err != nil {
+		return nil, err
+	}
+
+	body, err := utils.Download(url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return readTarGz(body, 1)
+}
+
+// readTarGz loads every regular file from a gzipped tar stream into a MemorySource.
+// Parameters:
+// - r: The gzipped tar stream.
+// - strip: The number of leading path components to remove from every entry.
+// Returns: A MemorySource holding the archive contents and an error if the archive could not be read.
+func readTarGz(r io.Reader, strip int) (*MemorySource, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip stream: %v", err)
+	}
+	defer gz.Close()
+
+	src := newMemorySource()
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar entry: %v", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name, ok := stripComponents(header.Name, strip)
+		if !ok {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archive: %v", header.Name, err)
+		}
+		src.add(name, content)
+	}
+
+	return src, nil
+}
+
+// stripComponents removes the first n slash separated components from name.
+// Returns: The remaining path and false when nothing is left after stripping.
+func stripComponents(name string, n int) (string, bool) {
+	parts := strings.Split(strings.Trim(name, "/"), "/")
+	if len(parts) <= n {
+		return consts.EMPTY_STRING, false
+	}
+	return strings.Join(parts[n:], "/"), true
+}
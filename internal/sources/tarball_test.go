@@ -0,0 +1,111 @@
+package sources
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+// buildTarGz creates a gzipped tarball holding the given files, in the order given.
+func buildTarGz(t *testing.T, files [][2]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file[0], Mode: 0644, Size: int64(len(file[1])), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(file[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func TestNewTarballSource(t *testing.T) {
+	archive := buildTarGz(t, [][2]string{
+		{"owner-repo-abc123/README.md", "readme"},
+		{"owner-repo-abc123/.licenseFiles/mit/LICENSE", "MIT"},
+		{"owner-repo-abc123/.workflowFiles/go/test.yml", "name: test"},
+	})
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/repos/owner/repo/tarball/master", r.URL.Path)
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	httpclient.Client = server.Client()
+
+	src, err := NewTarballSource(server.URL+"/repos/owner/repo/tarball/master", consts.EMPTY_STRING)
+	require.NoError(t, err)
+
+	items, err := src.ReadDir(consts.EMPTY_STRING)
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{
+		{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+		{Type: consts.DIR_TYPE, Name: ".workflowFiles", Path: ".workflowFiles"},
+		{Type: consts.FILE_TYPE, Name: "README.md", Path: "README.md"},
+	}, items)
+
+	items, err = src.ReadDir(".workflowFiles/go")
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{
+		{Type: consts.FILE_TYPE, Name: "test.yml", Path: ".workflowFiles/go/test.yml"},
+	}, items)
+
+	r, err := src.Open(".licenseFiles/mit/LICENSE")
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", string(content))
+
+	_, err = src.Open("missing")
+	assert.Error(t, err)
+	_, err = src.ReadDir("missing")
+	assert.Error(t, err)
+
+	assert.Equal(t, 1, requests, "the whole template should be fetched with a single request")
+}
+
+func TestNewTarballSourceErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		serverResponse func(w http.ResponseWriter, r *http.Request)
+	}{
+		{"HTTP error", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) }},
+		{"Invalid archive", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("not a tarball")) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
+			defer server.Close()
+
+			originalClient := httpclient.Client
+			defer func() { httpclient.Client = originalClient }()
+			httpclient.Client = server.Client()
+
+			src, err := NewTarballSource(server.URL, consts.EMPTY_STRING)
+			assert.Error(t, err)
+			assert.Nil(t, src)
+		})
+	}
+}
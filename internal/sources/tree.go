@@ -0,0 +1,90 @@
+package sources
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+// TreeSource reads a template from a GitHub repository whose layout was resolved with a single Git Trees API call.
+// File contents are only fetched, one blob request each, when a file is actually opened.
+type TreeSource struct {
+	// repoUrl is the API URL of the repository, e.g. https://api.github.com/repos/owner/name.
+	repoUrl string
+	// blobs maps the slash separated path of every file to its blob SHA.
+	blobs map[string]string
+	// index holds the directory structure of the template.
+	index dirIndex
+}
+
+// NewTreeSource resolves the whole tree of the repository at ref using GET /git/trees/{ref}?recursive=1.
+// When GitHub reports the listing as truncated, it falls back to a GitHubSource that lists the repository one directory at a time.
+// Parameters:
+// - repoUrl: The API URL of the repository.
+// - ref: The branch, tag or commit to read from.
+// - token: The GitHub token used to authenticate requests.
+// Returns: The TemplateSource for the repository and an error if the tree could not be resolved.
+func NewTreeSource(repoUrl, ref, token string) (TemplateSource, error) {
+	if err := ensureClient(token, httpclient.AuthorizationHeader, repoUrl); err != nil {
+		return nil, err
+	}
+
+	var tree types.GitTree
+	if err := getJSON(fmt.Sprintf("%s/git/trees/%s?recursive=1", repoUrl, ref), &tree); err != nil {
+		return nil, err
+	}
+
+	if tree.Truncated {
+		return NewGitHubSource(fmt.Sprintf("%s/contents", repoUrl), ref, token)
+	}
+
+	return newTreeSource(repoUrl, tree), nil
+}
+
+// newTreeSource builds the directory index of a TreeSource from a complete tree listing.
+func newTreeSource(repoUrl string, tree types.GitTree) *TreeSource {
+	src := &TreeSource{
+		repoUrl: repoUrl,
+		blobs:   make(map[string]string),
+		index:   newDirIndex(),
+	}
+
+	for _, entry := range tree.Tree {
+		if entry.Type != consts.BLOB_TYPE {
+			continue
+		}
+		src.blobs[src.index.addFile(entry.Path)] = entry.SHA
+	}
+
+	return src
+}
+
+// ReadDir lists the items directly beneath dir from the resolved tree without making any requests.
+// Parameters:
+// - dir: The slash separated directory relative to the repository root.
+// Returns: The items found in the directory and an error if the directory does not exist.
+func (s *TreeSource) ReadDir(dir string) ([]types.TemplateItem, error) {
+	return s.index.readDir(dir)
+}
+
+// Open fetches the raw contents of the blob at the given path.
+// Parameters:
+// - file: The slash separated path of the file relative to the repository root.
+// Returns: The raw blob contents and an error if the file is unknown or could not be fetched.
+func (s *TreeSource) Open(file string) (io.ReadCloser, error) {
+	sha, ok := s.blobs[cleanPath(file)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: file, Err: fs.ErrNotExist}
+	}
+
+	resp, err := doRequest(fmt.Sprintf("%s/git/blobs/%s", s.repoUrl, sha), map[string]string{"Accept": "application/vnd.github.raw"})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,82 @@
+package sources
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/httpclient"
+	"github-project-template/internal/types"
+)
+
+func TestNewTreeSource(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/repos/owner/repo/git/trees/master":
+			assert.Equal(t, "1", r.URL.Query().Get("recursive"))
+			json.NewEncoder(w).Encode(types.GitTree{Tree: []types.GitTreeEntry{
+				{Path: ".licenseFiles", Type: consts.TREE_TYPE, SHA: "t1"},
+				{Path: ".licenseFiles/mit", Type: consts.TREE_TYPE, SHA: "t2"},
+				{Path: ".licenseFiles/mit/LICENSE", Type: consts.BLOB_TYPE, SHA: "b1"},
+				{Path: "README.md", Type: consts.BLOB_TYPE, SHA: "b2"},
+			}})
+		case "/repos/owner/repo/git/blobs/b1":
+			assert.Equal(t, "application/vnd.github.raw", r.Header.Get("Accept"))
+			w.Write([]byte("MIT"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	httpclient.Client = server.Client()
+
+	src, err := NewTreeSource(server.URL+"/repos/owner/repo", "master", consts.EMPTY_STRING)
+	require.NoError(t, err)
+	require.IsType(t, &TreeSource{}, src)
+
+	items, err := src.ReadDir(consts.EMPTY_STRING)
+	require.NoError(t, err)
+	assert.Equal(t, []types.TemplateItem{
+		{Type: consts.DIR_TYPE, Name: ".licenseFiles", Path: ".licenseFiles"},
+		{Type: consts.FILE_TYPE, Name: "README.md", Path: "README.md"},
+	}, items)
+
+	r, err := src.Open(".licenseFiles/mit/LICENSE")
+	require.NoError(t, err)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", string(content))
+
+	_, err = src.Open("missing")
+	assert.Error(t, err)
+
+	assert.Equal(t, 1, requests["/repos/owner/repo/git/trees/master"])
+	assert.Equal(t, 0, requests["/repos/owner/repo/git/blobs/b2"], "blobs should only be fetched when opened")
+}
+
+func TestNewTreeSourceTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(types.GitTree{Truncated: true})
+	}))
+	defer server.Close()
+
+	originalClient := httpclient.Client
+	defer func() { httpclient.Client = originalClient }()
+	httpclient.Client = server.Client()
+
+	src, err := NewTreeSource(server.URL+"/repos/owner/repo", "master", consts.EMPTY_STRING)
+	require.NoError(t, err)
+	assert.IsType(t, &GitHubSource{}, src)
+}
@@ -0,0 +1,153 @@
+package trust
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/sources"
+)
+
+// Entry trusts a template source to run hooks.
+type Entry struct {
+	// Source is the repository or local path, as reported by sources.Origin.
+	Source string `yaml:"source"`
+	// SHA is the trusted commit; empty trusts every commit of the source.
+	SHA string `yaml:"sha,omitempty"`
+}
+
+// String formats the entry as source@sha, or source (any revision) when every commit is trusted.
+func (e Entry) String() string {
+	if e.SHA == consts.EMPTY_STRING {
+		return fmt.Sprintf("%s (any revision)", e.Source)
+	}
+	return fmt.Sprintf("%s@%s", e.Source, e.SHA)
+}
+
+// Store is the local list of template sources trusted to run hooks, kept in a YAML file.
+type Store struct {
+	path string
+	// Trusted are the trusted sources, in the order they were added.
+	Trusted []Entry `yaml:"trusted"`
+}
+
+// DefaultPath returns the location of the trust store, $HOME/.repo-stub-trust.yaml.
+// Returns: The path of the trust store and an error if the home directory is unknown.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return consts.EMPTY_STRING, fmt.Errorf("failed to locate the trust store: %v", err)
+	}
+	return filepath.Join(home, consts.TRUST_FILE), nil
+}
+
+// Load reads the trust store at path. A missing file is an empty store.
+// Parameters:
+// - path: The path of the trust store.
+// Returns: A pointer to the Store and an error if the file could not be read or parsed.
+func Load(path string) (*Store, error) {
+	store := &Store{path: path}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust store %s: %v", path, err)
+	}
+
+	if err := yaml.Unmarshal(content, store); err != nil {
+		return nil, fmt.Errorf("failed to parse trust store %s: %v", path, err)
+	}
+	return store, nil
+}
+
+// Save writes the trust store back to its file, readable only by the current user.
+// Returns: An error if the file could not be written.
+func (s *Store) Save() error {
+	content, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode trust store: %v", err)
+	}
+	if err := os.WriteFile(s.path, content, 0600); err != nil {
+		return fmt.Errorf("failed to save trust store %s: %v", s.path, err)
+	}
+	return nil
+}
+
+// Add trusts a source at the given SHA, or at every SHA when sha is empty.
+// Parameters:
+// - source: The repository or local path.
+// - sha: The trusted commit, or empty for every commit.
+// Returns: false if the entry was already trusted.
+func (s *Store) Add(source, sha string) bool {
+	entry := Entry{Source: source, SHA: sha}
+	for _, trusted := range s.Trusted {
+		if trusted == entry {
+			return false
+		}
+	}
+	s.Trusted = append(s.Trusted, entry)
+	return true
+}
+
+// Remove stops trusting a source at the given SHA, or every entry of the source when sha is empty.
+// Parameters:
+// - source: The repository or local path.
+// - sha: The commit to stop trusting, or empty for every entry of the source.
+// Returns: The number of removed entries.
+func (s *Store) Remove(source, sha string) int {
+	kept := s.Trusted[:0]
+	for _, trusted := range s.Trusted {
+		if trusted.Source == source && (sha == consts.EMPTY_STRING || trusted.SHA == sha) {
+			continue
+		}
+		kept = append(kept, trusted)
+	}
+
+	removed := len(s.Trusted) - len(kept)
+	s.Trusted = kept
+	return removed
+}
+
+// Trusts reports whether every origin is trusted, either at its SHA or at every SHA of its source.
+// No origins, i.e. a template whose origin is unknown, are never trusted.
+// Parameters:
+// - origins: Where the template was read from.
+// Returns: true if the hooks of the template may run without confirmation.
+func (s *Store) Trusts(origins []sources.Origin) bool {
+	return len(origins) > 0 && len(s.Untrusted(origins)) == 0
+}
+
+// Untrusted lists the origins that are not trusted, in order.
+// Parameters:
+// - origins: Where the template was read from.
+// Returns: The origins that are not trusted.
+func (s *Store) Untrusted(origins []sources.Origin) []sources.Origin {
+	var untrusted []sources.Origin
+	for _, origin := range origins {
+		if !s.trusts(origin) {
+			untrusted = append(untrusted, origin)
+		}
+	}
+	return untrusted
+}
+
+// trusts reports whether a single origin is trusted. Local directories and archives, identified by a content hash,
+// are only trusted at that hash, since whatever their path or URL serves later may differ.
+func (s *Store) trusts(origin sources.Origin) bool {
+	for _, trusted := range s.Trusted {
+		if trusted.Source != origin.Source {
+			continue
+		}
+		if trusted.SHA == origin.SHA || (trusted.SHA == consts.EMPTY_STRING && !origin.HasContentSHA()) {
+			return true
+		}
+	}
+	return false
+}
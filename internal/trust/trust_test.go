@@ -0,0 +1,75 @@
+package trust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/sources"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trust.yaml")
+
+	store, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, store.Trusted)
+
+	assert.True(t, store.Add("acme/templates", "abc123"))
+	assert.False(t, store.Add("acme/templates", "abc123"))
+	assert.True(t, store.Add("/home/jane/templates", ""))
+	require.NoError(t, store.Save())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	store, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{{Source: "acme/templates", SHA: "abc123"}, {Source: "/home/jane/templates"}}, store.Trusted)
+	assert.Equal(t, "acme/templates@abc123", store.Trusted[0].String())
+	assert.Equal(t, "/home/jane/templates (any revision)", store.Trusted[1].String())
+
+	assert.Equal(t, 0, store.Remove("acme/templates", "def456"))
+	assert.Equal(t, 1, store.Remove("acme/templates", ""))
+	assert.Equal(t, []Entry{{Source: "/home/jane/templates"}}, store.Trusted)
+
+	require.NoError(t, os.WriteFile(path, []byte("trusted: ["), 0600))
+	_, err = Load(path)
+	assert.Error(t, err)
+}
+
+func TestTrusts(t *testing.T) {
+	store := &Store{Trusted: []Entry{
+		{Source: "acme/templates", SHA: "abc123"},
+		{Source: "/home/jane/templates"},
+		{Source: "https://example.com/t.zip", SHA: "sha256:abc"},
+	}}
+
+	tests := []struct {
+		name     string
+		origins  []sources.Origin
+		expected bool
+	}{
+		{"Unknown origin", nil, false},
+		{"Trusted SHA", []sources.Origin{{Source: "acme/templates", SHA: "abc123"}}, true},
+		{"Other SHA", []sources.Origin{{Source: "acme/templates", SHA: "def456"}}, false},
+		{"Any revision", []sources.Origin{{Source: "/home/jane/templates"}}, true},
+		{"Untrusted layer", []sources.Origin{{Source: "/home/jane/templates"}, {Source: "evil/templates", SHA: "abc123"}}, false},
+		{"Content hash needs an exact match", []sources.Origin{{Source: "/home/jane/templates", SHA: "sha256:abc"}}, false},
+		{"Trusted content hash", []sources.Origin{{Source: "https://example.com/t.zip", SHA: "sha256:abc"}}, true},
+		{"Changed content hash", []sources.Origin{{Source: "https://example.com/t.zip", SHA: "sha256:def"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, store.Trusts(tt.origins))
+		})
+	}
+
+	assert.Equal(t, []sources.Origin{{Source: "evil/templates", SHA: "abc123"}, {Source: "local", SHA: "sha256:1"}},
+		store.Untrusted([]sources.Origin{{Source: "acme/templates", SHA: "abc123"}, {Source: "evil/templates", SHA: "abc123"}, {Source: "local", SHA: "sha256:1"}}))
+}
@@ -13,16 +13,96 @@ type GitHubItem struct {
 	DownloadURL string `json:"download_url"`
 }
 
+// GitLabItem represents an entry of the GitLab repository tree API, where Type is either "tree" or "blob".
+type GitLabItem struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// GitTree represents the response of the GitHub Git Trees API.
+type GitTree struct {
+	SHA       string         `json:"sha"`
+	Tree      []GitTreeEntry `json:"tree"`
+	Truncated bool           `json:"truncated"`
+}
+
+// GitTreeEntry represents a single blob or tree in a GitHub Git Trees API response.
+type GitTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+}
+
+// TemplateItem represents an entry exposed by a template source, independent of where the template is hosted.
+// Path is always slash separated and relative to the root of the template.
+type TemplateItem struct {
+	Type string
+	Name string
+	Path string
+}
+
+// PlannedFile describes a template file that will be written, as computed before anything is written.
+type PlannedFile struct {
+	// Source is the slash separated path of the file within the template.
+	Source string
+	// Target is the path the file is written to.
+	Target string
+	// Layer is the name of the template source layer providing the file, empty when the template is not layered.
+	Layer string
+}
+
+// Variable is a template variable value together with where it was set, e.g. "flag" for --var.
+type Variable struct {
+	Value  any
+	Source string
+}
+
 // CliFlags holds the flags passed by the user in the CLI, such as repository information and configuration options.
 type CliFlags struct {
+	ApiUrl             string
 	BranchName         string
+	FetchMode          string
 	IncludeMakefile    bool
 	IncludeVersionFile bool
+	Includes           []string
 	LicenseType        string
 	OutputDirectory    string
 	OverwriteFiles     bool
 	ProjectLanguage    string
+	Provider           string
+	Sources            []string
+	Template           string
+	VarAssignments     []string
+	VarsFile           string
+	Vars               map[string]Variable
+	NoInput            bool
+	AnswersFile        string
+	NoHooks            bool
+	GitInit            bool
+	InitialBranch      string
+	CommitMessage      string
+	GitAuthorName      string
+	GitAuthorEmail     string
+	GitNested          bool
 	GithubToken        string
 	RepoOwner          string
 	RepoName           string
 }
+
+// Repository represents the repository metadata shared by the GitHub, GitLab, Gitea and Forgejo APIs.
+type Repository struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+// Commit represents a single commit response, where GitHub, Gitea and Forgejo report the commit SHA as sha and GitLab as id.
+type Commit struct {
+	SHA string `json:"sha"`
+	ID  string `json:"id"`
+}
+
+// Tag represents an entry of the tag listing endpoints, where the tagged commit is reported as commit.sha by GitHub, Gitea and Forgejo and as commit.id by GitLab.
+type Tag struct {
+	Name   string `json:"name"`
+	Commit Commit `json:"commit"`
+}
@@ -1,455 +1,393 @@
 package repository
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
+	"path"
 	"path/filepath"
-	"strings"
+	"sort"
 	"sync"
 
-	// "github.com/gookit/color"
-
+	"github-project-template/internal/condition"
 	"github-project-template/internal/consts"
-	"github-project-template/internal/httpclient"
+	"github-project-template/internal/language"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/render"
+	"github-project-template/internal/sources"
 	"github-project-template/internal/types"
 	"github-project-template/internal/utils"
-)
 
-var (
-	wg sync.WaitGroup
+	"github.com/gookit/color"
 )
 
-// getRepoContents: Retrieves the contents of a GitHub repository based on the provided URL and path, using a GitHub token for authentication.
-// func getRepoContents(url, path, token string) ([]types.GitHubItem, error) {
-
-// 	if httpclient.Client == nil {
-// 		httpclient.InitClient(token)
-// 	}
-
-// 	// Append the path to the URL if specified
-// 	if path != consts.EMPTY_STRING {
-// 		url = fmt.Sprintf("%s/%s", url, path)
-// 	}
-
-// 	// Create a new request
-// 	req, err := http.NewRequest("GET", url, nil)
-// 	if err != nil {
-// 		return nil, err
-// 		// return nil, fmt.Errorf("failed to create request %s: %v", url, err)
-// 	}
-
-// 	// Perform the request using the global client
-// 	resp, err := httpclient.Client.Do(req)
-// 	if err != nil {
-// 		return nil, err
-// 		// return nil, fmt.Errorf("failed to get contents from '%s': %v", url, err)
-// 	}
-// 	defer resp.Body.Close()
-
-// 	// Check the response status code
-// 	if resp.StatusCode != http.StatusOK {
-// 		return nil, fmt.Errorf(": %s", utils.SetColor(color.FgLightRed, resp.Status))
-// 		// return nil, fmt.Errorf("failed to get contents %s: HTTP status %d", url, resp.StatusCode)
-// 	}
-
-// 	// Read the response body
-// 	body, err := io.ReadAll(resp.Body)
-// 	if err != nil {
-// 		// return nil, fmt.Errorf("failed to read response body: %v", err)
-// 		return nil, err
-// 	}
-
-// 	// Try to unmarshal the body as an array
-// 	var contents []types.GitHubItem
-// 	err = json.Unmarshal(body, &contents)
-// 	if err == nil {
-// 		// Return the contents, whether it's a single item or multiple items
-// 		return contents, nil
-// 	}
-
-// 	// If it's not an array, try to unmarshal as a single object
-// 	var singleItem types.GitHubItem
-// 	err = json.Unmarshal(body, &singleItem)
-// 	if err != nil {
-// 		// return nil, fmt.Errorf("failed to decode response %s: %v", url, err)
-// 		return nil, err
-// 	}
-
-// 	return []types.GitHubItem{singleItem}, nil
-// }
-
-func getRepoContents(url, path, token string) ([]types.GitHubItem, error) {
-	// Check if the client is initialized, if not, initialize it
-	if httpclient.Client == nil {
-		err := httpclient.InitClient(token)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize HTTP client: %v", err)
-		}
-	}
-
-	url = appendPathToUrl(url, path)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+// saveFile writes the file at the given template path to outputPath, opening it from the source only when it is actually written.
+// Files with the .tmpl suffix are rendered with the template data before they are written.
+// Parameters:
+// - src: The template source to read the file from.
+// - file: The slash separated path of the file within the template.
+// - outputPath: The location the file should be saved to.
+// - overwrite: A boolean indicating whether to overwrite an existing file.
+// - data: The values available to rendered templates.
+// Returns: Whether the file was written, false when it already existed and was skipped, and an error if any issues occur during the file retrieval, rendering or saving process.
+func saveFile(src sources.TemplateSource, file, outputPath string, overwrite bool, data render.Data) (bool, error) {
+	open := func() (io.ReadCloser, error) {
+		return src.Open(file)
 	}
-
-	resp, err := httpclient.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+	if render.IsTemplate(file) {
+		open = render.Open(open, file, data)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status: %v", resp.Status)
-	}
+	return utils.SaveFile(open, outputPath, overwrite)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// ProcessRepository processes the contents of a template source based on the provided CLI flags.
+// It computes the complete plan of files to write as described by the template manifest, reports which layer each file comes from when the source is layered,
+// and then writes the planned files concurrently, rendering .tmpl files with the data model built from the options.
+// Parameters:
+// - src: The template source to read from.
+// - m: The manifest describing the categories of the template.
+// - opts: CLI options of type types.CliFlags, including settings like output directory and overwrite flag.
+// Returns: The paths of the files that were written, sorted, and an error if any issues occur during processing or template content retrieval.
+func ProcessRepository(src sources.TemplateSource, m *manifest.Manifest, opts types.CliFlags) ([]string, error) {
+	data := render.NewData(opts)
+	plan, err := Plan(src, m, opts, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		return nil, err
 	}
 
-	var contents []types.GitHubItem
-	err = json.Unmarshal(body, &contents)
-	if err != nil {
-		var singleItem types.GitHubItem
-		err = json.Unmarshal(body, &singleItem)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode response %s: %v", url, err)
-		}
-		return []types.GitHubItem{singleItem}, nil
+	if len(plan) == 0 {
+		fmt.Println("no contents found")
+		return nil, nil
 	}
 
-	return contents, nil
+	reportLayers(plan)
+	return writePlan(src, plan, opts.OverwriteFiles, data), nil
 }
 
-// appendPathToUrl appends the given path to the URL if the path is not an empty string.
-// If the path is not empty, it ensures the URL is properly formatted with a "/" between the base URL and the path.
+// Plan walks the template source and computes the merged list of files to write, without writing anything.
+// Errors of individual categories are printed and the category is left out, matching how they are reported while writing.
 // Parameters:
-// - url: The base URL as a string.
-// - path: The path to append to the URL as a string.
-// Returns: A string representing the full URL with the appended path.
-func appendPathToUrl(url, path string) string {
-	if path != consts.EMPTY_STRING {
-		url = fmt.Sprintf("%s/%s", url, path)
-	}
-	return url
-}
-
-// createRequest creates a new HTTP request with the given URL and method.
-// It returns the request object and any error encountered during its creation.
-// Parameters:
-// - url: The URL for the request as a string.
-// - method: The HTTP method to use (e.g., "GET", "POST").
-// Returns: A pointer to an http.Request and an error (if any).
-func createRequest(url, method string) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, nil)
+// - src: The template source to read from.
+// - m: The manifest describing the categories of the template.
+// - opts: CLI options of type types.CliFlags, including the output directory and included optional categories.
+// - data: The values categories are selected and rendered with.
+// Returns: The planned files and an error if the template root could not be read.
+func Plan(src sources.TemplateSource, m *manifest.Manifest, opts types.CliFlags, data render.Data) ([]types.PlannedFile, error) {
+	plan, err := planDirectory(src, consts.EMPTY_STRING, m, opts, data)
 	if err != nil {
 		return nil, err
 	}
-	return req, nil
+
+	return resolveLayers(src, plan), nil
 }
 
-// ProcessRepository processes the contents of a repository at the given URL and path, based on the provided CLI flags.
-// It retrieves the contents of the repository and processes each item based on its type (file or directory).
-// Files are handled concurrently, while directories are processed sequentially.
+// planDirectory lists the given directory of the template and plans each item based on its type (file or directory),
+// leaving out the items whose manifest conditions do not hold.
 // Parameters:
-// - url: The repository URL as a string.
-// - path: The path within the repository as a string.
-// - opts: CLI options of type types.CliFlags, including settings like GitHub token, output directory, and overwrite flag.
-// Returns: An error if any issues occur during processing or repository content retrieval.
-func ProcessRepository(url, path string, opts types.CliFlags) error {
-	contents, err := getRepoContents(url, path, opts.GithubToken)
+// - src: The template source to read from.
+// - dir: The slash separated directory within the template.
+// - m: The manifest describing the categories of the template.
+// - opts: CLI options of type types.CliFlags.
+// - data: The values categories are selected and rendered with.
+// Returns: The planned files and an error if the directory could not be read or contains an unknown item type.
+func planDirectory(src sources.TemplateSource, dir string, m *manifest.Manifest, opts types.CliFlags, data render.Data) ([]types.PlannedFile, error) {
+	contents, err := src.ReadDir(dir)
 	if err != nil {
-		return err
-	}
-
-	if len(contents) == 0 {
-		fmt.Println("no contents found")
-		return nil
+		return nil, err
 	}
 
+	var plan []types.PlannedFile
 	for _, item := range contents {
+		allowed, err := m.Allows(item.Path, data)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if !allowed {
+			continue
+		}
+
 		switch item.Type {
 		case consts.FILE_TYPE:
-			wg.Add(1)
-			go func(item types.GitHubItem) {
-				defer wg.Done()
-				if err := handleFileTypeContent(item, opts.OutputDirectory, opts.OverwriteFiles); err != nil {
-					fmt.Println(err)
-				}
-			}(item)
+			files, err := handleFileTypeContent(item, m, opts.OutputDirectory, data)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			plan = append(plan, files...)
 		case consts.DIR_TYPE:
-			if err := handleDirectoryTypeContent(url, opts, item); err != nil {
+			files, err := handleDirectoryTypeContent(src, m, opts, data, item)
+			if err != nil {
 				fmt.Println(err)
+				continue
 			}
+			plan = append(plan, files...)
 		default:
-			return fmt.Errorf("unknown item.Type: %s found at %s", item.Type, item.Path)
+			return nil, fmt.Errorf("unknown item.Type: %s found at %s", item.Type, item.Path)
 		}
 	}
-	wg.Wait()
-	return nil
+
+	return plan, nil
 }
 
-// handleFileTypeContent processes a GitHub item of type "file".
-// It skips certain special files (e.g., README, LICENSE) and saves other files to the specified output path.
+// resolveLayers records the layer providing each planned file when the source is layered.
+// Files that no layer provides are reported and dropped from the plan.
 // Parameters:
-// - item: The GitHub item to process, of type types.GitHubItem.
-// - outputPath: The directory where the file should be saved.
-// - overwrite: A boolean indicating whether existing files should be overwritten.
-// Returns: An error if any issues occur during file saving.
-func handleFileTypeContent(item types.GitHubItem, outputPath string, overwrite bool) error {
-	switch item.Name {
-	case consts.README, consts.LICENSE, consts.GIT_IGNORE, consts.GIT_KEEP, consts.TODO:
-		// skip these they get handled in their relative funcs below
-		return nil
-	default:
-		return utils.SaveFile(item.DownloadURL, filepath.Join(outputPath, item.Path), overwrite)
+// - src: The template source the plan was computed from.
+// - plan: The planned files.
+// Returns: The planned files with their layers set.
+func resolveLayers(src sources.TemplateSource, plan []types.PlannedFile) []types.PlannedFile {
+	resolver, ok := src.(sources.LayerResolver)
+	if !ok {
+		return plan
 	}
-}
 
-// handleDirectoryTypeContent processes a GitHub item of type "directory".
-// Depending on the directory name, it delegates handling to specific functions for various types of files (e.g., ignore files, license files).
-// If the directory doesn't match any known special cases, it processes the repository recursively.
-// Parameters:
-// - url: The repository URL as a string.
-// - opts: CLI options of type types.CliFlags, including settings like project language, output directory, and overwrite flag.
-// - item: The GitHub item to process, of type types.GitHubItem.
-// Returns: An error if any issues occur during directory processing or file handling.
-func handleDirectoryTypeContent(url string, opts types.CliFlags, item types.GitHubItem) error {
-	switch item.Name {
-	case consts.IGNORE_FILES:
-		return handleIgnoreFiles(url, opts.ProjectLanguage, opts.OutputDirectory, opts.OverwriteFiles)
-	case consts.LICENSE_FILES:
-		return handleLicenseFiles(url, opts.LicenseType, opts.OutputDirectory, opts.OverwriteFiles)
-	case consts.MAKE_FILES:
-		return handleMakeFiles(url, opts.ProjectLanguage, opts.OutputDirectory, opts.OverwriteFiles, opts.IncludeMakefile)
-	case consts.README_FILES:
-		return handleReadmeFiles(url, opts.LicenseType, opts.OutputDirectory, opts.OverwriteFiles)
-	case consts.TODO_FILES:
-		return handleTodoFiles(url, opts.ProjectLanguage, opts.OutputDirectory, opts.OverwriteFiles)
-	case consts.RELEASE_FILES:
-		return handleReleaseFiles(url, opts.ProjectLanguage, opts.OutputDirectory, opts.OverwriteFiles)
-	case consts.VERSION_FILES:
-		return handleVersionFiles(url, opts.ProjectLanguage, opts.OutputDirectory, opts.OverwriteFiles, opts.IncludeVersionFile)
-	case consts.VSCODE_FILES:
-		return handleVSCodeFiles(url, opts.OutputDirectory, opts.OverwriteFiles)
-	case consts.WORKFLOW_FLIES:
-		return handleWorkflowFiles(url, opts.ProjectLanguage, opts.OutputDirectory, opts.GithubToken, opts.OverwriteFiles)
-	default:
-		return ProcessRepository(url, item.Path, opts)
+	resolved := make([]types.PlannedFile, 0, len(plan))
+	for _, file := range plan {
+		layer, err := resolver.LayerOf(file.Source)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		file.Layer = layer
+		resolved = append(resolved, file)
 	}
+
+	return resolved
 }
 
-// handleIgnoreFiles processes and saves the ignore files (e.g., .gitignore) for the specified project language.
-// It constructs the download URL based on the project language and fetches the file from the repository.
+// reportLayers prints which layer each planned file comes from, if the plan was computed from a layered source.
 // Parameters:
-// - url: The base repository URL as a string.
-// - projectLanguage: The programming language of the project, used to determine the ignore file to fetch.
-// - outputPath: The directory where the ignore file should be saved.
-// - overwrite: A boolean indicating whether to overwrite an existing ignore file.
-// Returns: An error if any issues occur during the file download or saving process.
-func handleIgnoreFiles(url, projectLanguage, outputPath string, overwrite bool) error {
-	contentUrl := fmt.Sprintf("%s/%s/%s/%s", url, consts.IGNORE_FILES, projectLanguage, consts.GIT_IGNORE)
-
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
-	if err != nil {
-		return err
+// - plan: The planned files.
+func reportLayers(plan []types.PlannedFile) {
+	for _, file := range plan {
+		if file.Layer != consts.EMPTY_STRING {
+			fmt.Printf("%s <- %s\n", color.New(color.FgCyan).Sprint(file.Target), file.Layer)
+		}
 	}
-
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, consts.GIT_IGNORE), overwrite)
 }
 
-// handleLicenseFiles processes and saves the license file for the specified license type.
-// It constructs the download URL based on the license type and fetches the license file from the repository.
+// writePlan writes every planned file concurrently, printing any errors.
 // Parameters:
-// - url: The base repository URL as a string.
-// - licenseType: The type of license to fetch (e.g., MIT, GPL).
-// - outputPath: The directory where the license file should be saved.
-// - overwrite: A boolean indicating whether to overwrite an existing license file.
-// Returns: An error if any issues occur during the file download or saving process.
-func handleLicenseFiles(url, licenseType, outputPath string, overwrite bool) error {
-	contentUrl := fmt.Sprintf("%s/%s/%s/%s", url, consts.LICENSE_FILES, licenseType, consts.LICENSE)
-
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
-	if err != nil {
-		return err
+// - src: The template source to read the files from.
+// - plan: The planned files.
+// - overwrite: A boolean indicating whether existing files should be overwritten.
+// - data: The values available to rendered templates.
+// Returns: The paths of the files that were written, sorted, leaving out files that failed or already existed and were skipped.
+func writePlan(src sources.TemplateSource, plan []types.PlannedFile, overwrite bool, data render.Data) []string {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		written []string
+	)
+	for _, file := range plan {
+		wg.Add(1)
+		go func(file types.PlannedFile) {
+			defer wg.Done()
+			saved, err := saveFile(src, file.Source, file.Target, overwrite, data)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			if saved {
+				mu.Lock()
+				written = append(written, file.Target)
+				mu.Unlock()
+			}
+		}(file)
 	}
+	wg.Wait()
 
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, consts.LICENSE), overwrite)
+	sort.Strings(written)
+	return written
 }
 
-// handleMakeFiles processes and saves the Makefile for the specified project language, if the includeMakefile option is set to true.
-// It constructs the download URL based on the project language and fetches the Makefile from the repository.
-// If includeMakefile is false, the function returns without performing any actions.
+// handleFileTypeContent plans a template item of type "file".
+// It skips the files the manifest lists (e.g., README, LICENSE, with or without the .tmpl suffix), which are planned by their categories,
+// and plans every other file at its rendered relative path in the output directory, e.g. cmd/{{ .binary_name }}/main.go.
+// Files whose path renders empty are left out.
 // Parameters:
-// - url: The base repository URL as a string.
-// - projectLanguage: The programming language of the project, used to determine the appropriate Makefile to fetch.
-// - outputPath: The directory where the Makefile should be saved.
-// - overwrite: A boolean indicating whether to overwrite an existing Makefile.
-// - includeMakefile: A boolean indicating whether to include the Makefile in the process.
-// Returns: An error if any issues occur during the file download or saving process.
-func handleMakeFiles(url, projectLanguage, outputPath string, overwrite, includeMakefile bool) error {
-	if !includeMakefile {
-		return nil
+// - item: The template item to plan, of type types.TemplateItem.
+// - m: The manifest listing the skipped files.
+// - outputPath: The directory where the file should be saved.
+// - data: The values the path is rendered with.
+// Returns: The planned file, or nothing if the file is skipped, and an error if the path could not be rendered.
+func handleFileTypeContent(item types.TemplateItem, m *manifest.Manifest, outputPath string, data render.Data) ([]types.PlannedFile, error) {
+	if m.Skips(render.TargetPath(item.Name)) {
+		return nil, nil
 	}
-	contentUrl := fmt.Sprintf("%s/%s/%s/%s", url, consts.MAKE_FILES, projectLanguage, consts.MAKEFILE)
 
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
-	if err != nil {
-		return err
+	file, err := render.Path(item.Path, data)
+	if err != nil || file == consts.EMPTY_STRING {
+		return nil, err
 	}
 
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, consts.MAKEFILE), overwrite)
+	return planFile(item.Path, filepath.Join(outputPath, filepath.FromSlash(file))), nil
 }
 
-// handleReadmeFiles processes and saves the README file for the specified license type.
-// It constructs the download URL based on the license type and fetches the README file from the repository.
+// handleDirectoryTypeContent plans a template item of type "directory".
+// Directories the manifest declares as categories are planned by planCategory from that directory, and any other directory is planned recursively.
 // Parameters:
-// - url: The base repository URL as a string.
-// - licenseType: The type of license, used to determine the appropriate README file to fetch.
-// - outputPath: The directory where the README file should be saved.
-// - overwrite: A boolean indicating whether to overwrite an existing README file.
-// Returns: An error if any issues occur during the file download or saving process.
-func handleReadmeFiles(url, licenseType, outputPath string, overwrite bool) error {
-	contentUrl := fmt.Sprintf("%s/%s/%s/%s", url, consts.README_FILES, licenseType, consts.README)
-
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
-	if err != nil {
-		return err
+// - src: The template source to read from.
+// - m: The manifest describing the categories of the template.
+// - opts: CLI options of type types.CliFlags, including the output directory and included optional categories.
+// - data: The values categories are selected and rendered with.
+// - item: The template item to plan, of type types.TemplateItem.
+// Returns: The planned files and an error if any issues occur during directory processing.
+func handleDirectoryTypeContent(src sources.TemplateSource, m *manifest.Manifest, opts types.CliFlags, data render.Data, item types.TemplateItem) ([]types.PlannedFile, error) {
+	if category := m.Category(item.Path); category != nil {
+		return planCategory(src, m, *category, item.Path, opts, data)
 	}
 
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, consts.README), overwrite)
+	return planDirectory(src, item.Path, m, opts, data)
 }
 
-// handleTodoFiles processes and saves the TODO file for the specified project language.
-// It constructs the download URL based on the project language and fetches the TODO file from the repository.
+// planFile plans a single template file to be written to outputPath, stripping the .tmpl suffix from the target of rendered files.
 // Parameters:
-// - url: The base repository URL as a string.
-// - projectLanguage: The programming language of the project, used to determine the appropriate TODO file to fetch.
-// - outputPath: The directory where the TODO file should be saved.
-// - overwrite: A boolean indicating whether to overwrite an existing TODO file.
-// Returns: An error if any issues occur during the file download or saving process.
-func handleTodoFiles(url, projectLanguage, outputPath string, overwrite bool) error {
-	contentUrl := fmt.Sprintf("%s/%s/%s/%s", url, consts.TODO_FILES, projectLanguage, consts.TODO)
-
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
-	if err != nil {
-		return err
+// - file: The slash separated path of the file within the template.
+// - outputPath: The location the file should be saved to.
+// Returns: A plan containing the single file.
+func planFile(file, outputPath string) []types.PlannedFile {
+	if render.IsTemplate(file) {
+		outputPath = render.TargetPath(outputPath)
 	}
-
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, consts.TODO), overwrite)
+	return []types.PlannedFile{{Source: file, Target: outputPath}}
 }
 
-// handleVSCodeFiles processes and saves the VSCode configuration file (commands.json) for the repository.
-// It constructs the download URL for the VSCode files and fetches the commands.json file from the repository.
+// planCategory plans the files of a category: the files of the subdirectory selected by the category's selector variable
+// whose rendered names, without the .tmpl suffix, match one of its patterns, written to its rendered destination directory.
+// A language selects the subdirectory the language registry names for the category, e.g. its ignore key for ignore files.
+// Optional categories are only planned when they are included, categories with a condition only when it holds,
+// and files only when the manifest conditions matching them hold. When a file exists both with and without the .tmpl suffix, the template wins.
 // Parameters:
-// - url: The base repository URL as a string.
-// - outputPath: The directory where the VSCode commands.json file should be saved.
-// - overwrite: A boolean indicating whether to overwrite an existing commands.json file.
-// Returns: An error if any issues occur during the file download or saving process.
-func handleVSCodeFiles(url, outputPath string, overwrite bool) error {
-	contentUrl := fmt.Sprintf("%s/%s/commands.json", url, consts.VSCODE_FILES)
-
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
-	if err != nil {
-		return err
-	}
-
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, consts.VSCODE, "commands.json"), overwrite)
-}
-
-func handleVersionFiles(url, projectLanguage, outputPath string, overwrite, includeVersionFile bool) error {
-	if !includeVersionFile {
-		return nil
+// - src: The template source to read from.
+// - m: The manifest holding the file conditions.
+// - category: The category to plan.
+// - dir: The slash separated directory of the category within the template, which is found by its name at any depth.
+// - opts: CLI options of type types.CliFlags, including the output directory and included optional categories.
+// - data: The values the category is selected and its patterns are rendered with.
+// Returns: The planned files and an error if the selector is not set, a pattern or the destination is invalid or no file matches.
+func planCategory(src sources.TemplateSource, m *manifest.Manifest, category manifest.Category, dir string, opts types.CliFlags, data render.Data) ([]types.PlannedFile, error) {
+	if category.Optional && !included(opts.Includes, category.Name) {
+		return nil, nil
 	}
-	versionFile, err := utils.GetVersionFile(projectLanguage)
+	enabled, err := condition.Eval(category.When, data)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("category %s: %v", category.Name, err)
 	}
-	if versionFile == consts.EMPTY_STRING {
-		return fmt.Errorf("no version file for %s", projectLanguage)
+	if !enabled {
+		return nil, nil
 	}
 
-	// Construct the content URL
-	contentUrl := fmt.Sprintf("%s/%s/%s/%s", url, consts.VERSION_FILES, projectLanguage, versionFile)
+	if category.Selector != consts.EMPTY_STRING {
+		value, ok := data[category.Selector]
+		if !ok || value == nil || fmt.Sprint(value) == consts.EMPTY_STRING {
+			return nil, fmt.Errorf("category %s: variable %s is not set", category.Name, category.Selector)
+		}
+		key := fmt.Sprint(value)
+		if category.Selector == render.LANGUAGE {
+			key = language.Key(key, category.Name)
+		}
+		dir = path.Join(dir, key)
+	}
 
-	// Get the download URL
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
+	patterns, err := renderPatterns(category, data)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	// Save the file
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, versionFile), overwrite)
-}
-
-// handleVersionFiles processes and saves the version file for the specified project language, if the includeVersionFile option is set to true.
-// It retrieves the appropriate version file name based on the project language and constructs the download URL to fetch the file from the repository.
-// If includeVersionFile is false, the function returns without performing any actions.
-// Parameters:
-// - url: The base repository URL as a string.
-// - projectLanguage: The programming language of the project, used to determine the appropriate version file to fetch.
-// - outputPath: The directory where the version file should be saved.
-// - overwrite: A boolean indicating whether to overwrite an existing version file.
-// - includeVersionFile: A boolean indicating whether to include the version file in the process.
-// Returns: An error if any issues occur during the file retrieval or saving process.
-func handleReleaseFiles(url, projectLanguage, outputPath string, overwrite bool) error {
-	// Get the release file for the specified language
-	releaseFile, err := utils.GetReleaseFile(projectLanguage)
+	dest, err := render.Path(category.Dest, data)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("category %s: %v", category.Name, err)
 	}
-	if releaseFile == consts.EMPTY_STRING {
-		return fmt.Errorf("no release file for %s", projectLanguage)
+
+	contents, err := src.ReadDir(dir)
+	if err != nil {
+		return nil, err
 	}
 
-	// Construct the content URL
-	contentUrl := fmt.Sprintf("%s/%s/%s/%s", url, consts.RELEASE_FILES, projectLanguage, releaseFile)
+	targets := make(map[string]int)
+	var plan []types.PlannedFile
+	for _, item := range contents {
+		if item.Type != consts.FILE_TYPE {
+			continue
+		}
+		allowed, err := m.Allows(item.Path, data)
+		if err != nil {
+			return nil, fmt.Errorf("category %s: %v", category.Name, err)
+		}
+		if !allowed {
+			continue
+		}
 
-	// Get the download URL
-	downloadUrl, err := utils.GrabDownloadUrl(contentUrl)
-	if err != nil {
-		return err
+		name, err := render.Path(item.Name, data)
+		if err != nil {
+			return nil, fmt.Errorf("category %s: %v", category.Name, err)
+		}
+		if name == consts.EMPTY_STRING || !matchesAny(patterns, render.TargetPath(name)) {
+			continue
+		}
+
+		file := planFile(item.Path, filepath.Join(opts.OutputDirectory, filepath.FromSlash(dest), category.Prefix+name))[0]
+		if idx, exists := targets[file.Target]; exists {
+			if render.IsTemplate(item.Name) {
+				plan[idx] = file
+			}
+			continue
+		}
+		targets[file.Target] = len(plan)
+		plan = append(plan, file)
 	}
 
-	// Save the file
-	return utils.SaveFile(downloadUrl, filepath.Join(outputPath, fmt.Sprintf(".%s", releaseFile)), overwrite)
+	if len(plan) == 0 {
+		return nil, fmt.Errorf("category %s: no files found in %s", category.Name, dir)
+	}
+	return plan, nil
 }
 
-// handleWorkflowFiles processes and saves workflow files (with .yml extension) for the specified project language.
-// It constructs the URL to fetch workflow files, retrieves the contents of the repository, and saves each workflow file to the specified output directory.
+// renderPatterns renders the file patterns of a category, leaving out patterns that render empty.
+// A category without patterns matches every file.
 // Parameters:
-// - url: The base repository URL as a string.
-// - projectLanguage: The programming language of the project, used to determine the workflow files to fetch.
-// - outputPath: The directory where the workflow files should be saved.
-// - token: The authentication token to access private repositories.
-// - overwrite: A boolean indicating whether to overwrite existing workflow files.
-// Returns: An error if any issues occur during file retrieval or saving.
-func handleWorkflowFiles(url, projectLanguage, outputPath, token string, overwrite bool) error {
-	// Build the initial URL for workflow files
-	url = fmt.Sprintf("%s/%s/%s", url, consts.WORKFLOW_FLIES, projectLanguage)
-
-	// Fetch the contents of the repository
-	contents, err := getRepoContents(url, consts.EMPTY_STRING, token)
-	if err != nil {
-		return err
+// - category: The category whose patterns are rendered.
+// - data: The values the patterns are rendered with.
+// Returns: The rendered patterns and an error if a pattern could not be rendered or is not a valid glob.
+func renderPatterns(category manifest.Category, data render.Data) ([]string, error) {
+	if len(category.Files) == 0 {
+		return []string{"*"}, nil
 	}
 
-	// Iterate through the contents
-	for _, item := range contents {
-		if item.Type == consts.FILE_TYPE && strings.HasSuffix(item.Name, consts.YML) {
-			// Join the output path correctly for each file
-			fileOutputPath := filepath.Join(outputPath, consts.GIT_HUB, consts.WORKFLOW, item.Name)
+	var patterns []string
+	for _, file := range category.Files {
+		pattern, err := render.Render(category.Name, []byte(file), data)
+		if err != nil {
+			return nil, fmt.Errorf("category %s: %v", category.Name, err)
+		}
+		if len(pattern) == 0 {
+			continue
+		}
+		if _, err := path.Match(string(pattern), consts.EMPTY_STRING); err != nil {
+			return nil, fmt.Errorf("category %s: invalid pattern %s: %v", category.Name, pattern, err)
+		}
+		patterns = append(patterns, string(pattern))
+	}
+	return patterns, nil
+}
 
-			// Save the file
-			if err := utils.SaveFile(item.DownloadURL, fileOutputPath, overwrite); err != nil {
-				return err
-			}
+// matchesAny reports whether name matches one of the glob patterns.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
 		}
 	}
+	return false
+}
 
-	return nil
+// included reports whether the optional category with the given name was included.
+func included(includes []string, name string) bool {
+	for _, include := range includes {
+		if include == name {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/render"
+	"github-project-template/internal/sources"
+	"github-project-template/internal/types"
+)
+
+// defaultTemplate holds a template laid out for the default manifest, with a templated path and a conditional file.
+var defaultTemplate = map[string]string{
+	".ignoreFiles/go/.gitignore":                "bin/",
+	".licenseFiles/mit/LICENSE":                 "MIT",
+	".licenseFiles/mit/LICENSE.tmpl":            "MIT {{ .owner }}",
+	".makeFiles/go/Makefile":                    "build:",
+	".readmeFiles/mit/README.md":                "readme",
+	".releaseFiles/go/goreleaser.yaml":          "builds:",
+	".todoFiles/go/TODO":                        "todo",
+	".versionFiles/go/version.go":               "package main",
+	".vscodeFiles/commands.json":                "{}",
+	".workflowFiles/go/test.yml":                "name: test",
+	".workflowFiles/go/notes.md":                "not a workflow",
+	"README.md":                                 "template readme",
+	"cmd/{{ .binary_name }}/main.go":            "package main",
+	"build/{{ if .docker }}Dockerfile{{ end }}": "FROM scratch",
+	"docs/guide.md":                             "guide",
+}
+
+// planned is a planned file with its target relative to the output directory, for comparing plans.
+type planned struct {
+	Source string
+	Target string
+	Layer  string
+}
+
+// plannedFiles converts a plan into planned files with targets relative to output.
+func plannedFiles(t *testing.T, output string, plan []types.PlannedFile) []planned {
+	files := make([]planned, 0, len(plan))
+	for _, file := range plan {
+		target, err := filepath.Rel(output, file.Target)
+		require.NoError(t, err)
+		files = append(files, planned{Source: file.Source, Target: filepath.ToSlash(target), Layer: file.Layer})
+	}
+	return files
+}
+
+func TestPlan(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "my-app")
+
+	tests := []struct {
+		name     string
+		src      sources.TemplateSource
+		m        *manifest.Manifest
+		opts     types.CliFlags
+		vars     map[string]any
+		expected []planned
+	}{
+		{
+			name: "Default layout",
+			src:  sources.NewMemorySource(defaultTemplate),
+			m:    manifest.Default(),
+			vars: map[string]any{"binary_name": "tool", "docker": false},
+			expected: []planned{
+				{Source: ".ignoreFiles/go/.gitignore", Target: ".gitignore"},
+				{Source: ".releaseFiles/go/goreleaser.yaml", Target: ".goreleaser.yaml"},
+				{Source: ".vscodeFiles/commands.json", Target: ".vscode/commands.json"},
+				{Source: ".workflowFiles/go/test.yml", Target: ".github/workflows/test.yml"},
+				{Source: ".licenseFiles/mit/LICENSE.tmpl", Target: "LICENSE"},
+				{Source: ".readmeFiles/mit/README.md", Target: "README.md"},
+				{Source: ".todoFiles/go/TODO", Target: "TODO"},
+				{Source: "cmd/{{ .binary_name }}/main.go", Target: "cmd/tool/main.go"},
+				{Source: "docs/guide.md", Target: "docs/guide.md"},
+			},
+		},
+		{
+			name: "Included optional categories and conditional file",
+			src:  sources.NewMemorySource(defaultTemplate),
+			m:    manifest.Default(),
+			opts: types.CliFlags{Includes: []string{consts.CATEGORY_MAKEFILE, consts.CATEGORY_VERSION}},
+			vars: map[string]any{"binary_name": "tool", "docker": true},
+			expected: []planned{
+				{Source: ".ignoreFiles/go/.gitignore", Target: ".gitignore"},
+				{Source: ".releaseFiles/go/goreleaser.yaml", Target: ".goreleaser.yaml"},
+				{Source: ".vscodeFiles/commands.json", Target: ".vscode/commands.json"},
+				{Source: ".workflowFiles/go/test.yml", Target: ".github/workflows/test.yml"},
+				{Source: ".licenseFiles/mit/LICENSE.tmpl", Target: "LICENSE"},
+				{Source: ".makeFiles/go/Makefile", Target: "Makefile"},
+				{Source: ".readmeFiles/mit/README.md", Target: "README.md"},
+				{Source: ".todoFiles/go/TODO", Target: "TODO"},
+				{Source: "build/{{ if .docker }}Dockerfile{{ end }}", Target: "build/Dockerfile"},
+				{Source: "cmd/{{ .binary_name }}/main.go", Target: "cmd/tool/main.go"},
+				{Source: "docs/guide.md", Target: "docs/guide.md"},
+				{Source: ".versionFiles/go/version.go", Target: "version.go"},
+			},
+		},
+		{
+			name: "Python project",
+			src: sources.NewMemorySource(map[string]string{
+				".ignoreFiles/go/.gitignore":           "bin/",
+				".ignoreFiles/python/.gitignore":       "__pycache__/",
+				".licenseFiles/mit/LICENSE":            "MIT",
+				".makeFiles/python/Makefile":           "test:",
+				".readmeFiles/mit/README.md":           "readme",
+				".releaseFiles/go/goreleaser.yaml":     "builds:",
+				".releaseFiles/python/bumpversion.cfg": "[bumpversion]",
+				".todoFiles/python/TODO":               "todo",
+				".versionFiles/go/version.go":          "package main",
+				".versionFiles/python/__version__.py":  "__version__ = \"0.1.0\"",
+				".workflowFiles/python/test.yml":       "name: test",
+			}),
+			m:    manifest.Default(),
+			opts: types.CliFlags{ProjectLanguage: consts.PY_LANG, Includes: []string{consts.CATEGORY_MAKEFILE, consts.CATEGORY_VERSION}},
+			expected: []planned{
+				{Source: ".ignoreFiles/python/.gitignore", Target: ".gitignore"},
+				{Source: ".releaseFiles/python/bumpversion.cfg", Target: ".bumpversion.cfg"},
+				{Source: ".workflowFiles/python/test.yml", Target: ".github/workflows/test.yml"},
+				{Source: ".licenseFiles/mit/LICENSE", Target: "LICENSE"},
+				{Source: ".makeFiles/python/Makefile", Target: "Makefile"},
+				{Source: ".readmeFiles/mit/README.md", Target: "README.md"},
+				{Source: ".todoFiles/python/TODO", Target: "TODO"},
+				{Source: ".versionFiles/python/__version__.py", Target: "my_app/__version__.py"},
+			},
+		},
+		{
+			name: "Manifest categories, skips and file conditions",
+			src: sources.NewMemorySource(map[string]string{
+				"templates/licenses/mit/LICENSE.tmpl": "MIT",
+				"templates/licenses/mit/NOTICE":       "notice",
+				"licenses/mit/LICENSE":                "not a category",
+				"docker/Dockerfile":                   "FROM scratch",
+				"CHANGELOG.md":                        "skipped",
+				consts.MANIFEST:                       "categories: []",
+			}),
+			m: &manifest.Manifest{
+				Categories: []manifest.Category{
+					{Name: "licenses", Dir: "templates/licenses", Selector: render.LICENSE, Files: []string{"{{ .license_file }}"}, Dest: "legal", Prefix: "_"},
+				},
+				Skip:  []string{"CHANGELOG.md"},
+				Files: []manifest.File{{Path: "docker", When: ".docker"}},
+			},
+			vars: map[string]any{"license_file": consts.LICENSE, "docker": "false"},
+			expected: []planned{
+				{Source: "templates/licenses/mit/LICENSE.tmpl", Target: "legal/_LICENSE"},
+				{Source: "licenses/mit/LICENSE", Target: "licenses/mit/LICENSE"},
+			},
+		},
+		{
+			name: "Category directories found by name at any depth",
+			src: sources.NewMemorySource(map[string]string{
+				"go-cli/.licenseFiles/mit/LICENSE": "MIT",
+				"go-cli/main.go":                   "package main",
+			}),
+			m: manifest.Default(),
+			expected: []planned{
+				{Source: "go-cli/.licenseFiles/mit/LICENSE", Target: "LICENSE"},
+				{Source: "go-cli/main.go", Target: "go-cli/main.go"},
+			},
+		},
+		{
+			name: "Layered sources",
+			src: sources.NewLayeredSource(
+				sources.Layer{Name: "org/base", Source: sources.NewMemorySource(map[string]string{
+					".licenseFiles/mit/LICENSE": "base MIT",
+					"Makefile":                  "base",
+				})},
+				sources.Layer{Name: "team/overlay", Source: sources.NewMemorySource(map[string]string{
+					".licenseFiles/mit/LICENSE": "team MIT",
+					"docs/guide.md":             "guide",
+				})},
+			),
+			m: &manifest.Manifest{
+				Categories: []manifest.Category{{Name: consts.CATEGORY_LICENSE, Dir: consts.LICENSE_FILES, Selector: render.LICENSE}},
+			},
+			expected: []planned{
+				{Source: ".licenseFiles/mit/LICENSE", Target: "LICENSE", Layer: "team/overlay"},
+				{Source: "Makefile", Target: "Makefile", Layer: "org/base"},
+				{Source: "docs/guide.md", Target: "docs/guide.md", Layer: "team/overlay"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.OutputDirectory = output
+			if opts.ProjectLanguage == consts.EMPTY_STRING {
+				opts.ProjectLanguage = consts.GO_LANG
+			}
+			opts.LicenseType = "mit"
+			opts.Vars = make(map[string]types.Variable)
+			for key, value := range tt.vars {
+				opts.Vars[key] = types.Variable{Value: value, Source: consts.VAR_SOURCE_FLAG}
+			}
+
+			plan, err := Plan(tt.src, tt.m, opts, render.NewData(opts))
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, plannedFiles(t, output, plan))
+		})
+	}
+}
+
+func TestWritePlan(t *testing.T) {
+	output := t.TempDir()
+	src := sources.NewMemorySource(map[string]string{"README.md": "readme", "main.go": "package main", "LICENSE.tmpl": "MIT {{ .owner }}"})
+	plan := []types.PlannedFile{
+		{Source: "README.md", Target: filepath.Join(output, "README.md")},
+		{Source: "main.go", Target: filepath.Join(output, "cmd", "main.go")},
+		{Source: "LICENSE.tmpl", Target: filepath.Join(output, "LICENSE")},
+		{Source: "missing.go", Target: filepath.Join(output, "missing.go")},
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(output, "README.md"), []byte("existing"), 0644))
+	data := render.Data{render.OWNER: "jane"}
+
+	tests := []struct {
+		name      string
+		overwrite bool
+		expected  []string
+		readme    string
+	}{
+		{"Existing files are skipped", false, []string{filepath.Join(output, "LICENSE"), filepath.Join(output, "cmd", "main.go")}, "existing"},
+		{"Existing files are overwritten", true, []string{filepath.Join(output, "LICENSE"), filepath.Join(output, "README.md"), filepath.Join(output, "cmd", "main.go")}, "readme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, writePlan(src, plan, tt.overwrite, data))
+
+			readme, err := os.ReadFile(filepath.Join(output, "README.md"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.readme, string(readme))
+			license, err := os.ReadFile(filepath.Join(output, "LICENSE"))
+			require.NoError(t, err)
+			assert.Equal(t, "MIT jane", string(license))
+		})
+	}
+}
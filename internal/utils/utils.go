@@ -1,18 +1,17 @@
 package utils
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"github-project-template/internal/consts"
 	"github-project-template/internal/httpclient"
+	"github-project-template/internal/language"
 	"github-project-template/internal/spinner"
 	"github-project-template/internal/types"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/gookit/color"
@@ -69,19 +68,51 @@ func GrabDownloadUrl(url string) (string, error) {
 	return data.DownloadURL, nil
 }
 
-func SaveFile(url, outputPath string, overwrite bool) error {
-	return SaveFileWithSpinner(url, outputPath, overwrite, spinner.CreateSpinner, &FileOps{})
+// OpenFunc lazily opens the content written by SaveFile, so nothing is fetched for files that end up being skipped.
+type OpenFunc func() (io.ReadCloser, error)
+
+// Download performs a GET request against url using the shared HTTP client and returns the response body.
+// The caller is responsible for closing the returned reader.
+func Download(url string) (io.ReadCloser, error) {
+	if httpclient.Client == nil {
+		return nil, fmt.Errorf("HTTP client is not initialized")
+	}
+	return DownloadWith(httpclient.Client, url)
+}
+
+// DownloadWith performs a GET request against url using client and returns the response body,
+// for downloads that must not go through the shared, authenticated HTTP client.
+// The caller is responsible for closing the returned reader.
+func DownloadWith(client httpclient.HTTPClient, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %v", url, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contents from %s: %v", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to get contents %s: HTTP status %d", url, resp.StatusCode)
+	}
+
+	return resp.Body, nil
 }
 
-func SaveFileWithSpinner(url, outputPath string, overwrite bool, spinnerCreator func() (spinner.SpinnerInterface, error), fileOps FileOpsInterface) error {
-	// Create a context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// SaveFile writes the content opened by open to outputPath, skipping files that already exist unless overwrite is set.
+// It reports whether the file was written, so callers do not need to check for existing files themselves.
+func SaveFile(open OpenFunc, outputPath string, overwrite bool) (bool, error) {
+	return SaveFileWithSpinner(open, outputPath, overwrite, spinner.CreateSpinner, &FileOps{})
+}
 
+func SaveFileWithSpinner(open OpenFunc, outputPath string, overwrite bool, spinnerCreator func() (spinner.SpinnerInterface, error), fileOps FileOpsInterface) (bool, error) {
 	// Create a spinner with the DefaultSpinnerFactory
 	s, err := spinnerCreator()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Ensure the spinner stops when the function exits
@@ -92,7 +123,7 @@ func SaveFileWithSpinner(url, outputPath string, overwrite bool, spinnerCreator
 
 	// Start the spinner
 	if err := s.Start(); err != nil {
-		return err
+		return false, err
 	}
 
 	// Show initial processing message
@@ -104,79 +135,72 @@ func SaveFileWithSpinner(url, outputPath string, overwrite bool, spinnerCreator
 	if _, err := fileOps.Stat(outputPath); err == nil && !overwrite {
 		s.StopMessage(fmt.Sprintf("Skipped %s", color.New(color.FgRed).Sprint(file)))
 		time.Sleep(500 * time.Millisecond)
-		return nil
+		return false, nil
 	}
 
 	// Create the directory structure if needed
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		s.StopFailMessage(fmt.Sprintf("failed to create directory structure for '%s': %v", outputPath, err))
-		return fmt.Errorf("failed to create directory structure for '%s': %v", outputPath, err)
-	}
-
-	// Create a new request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		s.StopFailMessage(fmt.Sprintf("failed to create request for %s: %v", url, err))
-		return fmt.Errorf("failed to create request for %s: %v", url, err)
+		return false, fmt.Errorf("failed to create directory structure for '%s': %v", outputPath, err)
 	}
 
-	// Download the file
-	resp, err := httpclient.Client.Do(req)
+	// Open the content
+	in, err := open()
 	if err != nil {
-		s.StopFailMessage(fmt.Sprintf("failed to get contents from %s: %v", url, err))
-		return fmt.Errorf("failed to get contents from %s: %v", url, err)
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		s.StopFailMessage(fmt.Sprintf("failed to get contents %s: HTTP status %d", url, resp.StatusCode))
-		return fmt.Errorf("failed to get contents %s: HTTP status %d", url, resp.StatusCode)
+		s.StopFailMessage(fmt.Sprintf("failed to get contents for '%s': %v", outputPath, err))
+		return false, fmt.Errorf("failed to get contents for '%s': %v", outputPath, err)
 	}
+	defer in.Close()
 
 	// Create the output file
 	out, err := fileOps.Create(outputPath)
 	if err != nil {
 		s.StopFailMessage(fmt.Sprintf("failed to create file '%s': %v", outputPath, err))
-		return fmt.Errorf("failed to create file '%s': %v", outputPath, err)
+		return false, fmt.Errorf("failed to create file '%s': %v", outputPath, err)
 	}
 	defer out.Close()
 
-	// Copy response body to the file
-	if _, err := io.Copy(out, resp.Body); err != nil {
+	// Copy the content to the file
+	if _, err := io.Copy(out, in); err != nil {
 		s.StopFailMessage(fmt.Sprintf("failed to write file '%s': %v", outputPath, err))
-		return fmt.Errorf("failed to write file '%s': %v", outputPath, err)
+		return false, fmt.Errorf("failed to write file '%s': %v", outputPath, err)
 	}
 
 	// Show success message
 	time.Sleep(500 * time.Millisecond)
 	s.StopMessage(fmt.Sprintf("Processed %s saved in %s", color.New(color.FgGreen).Sprint(file), color.New(color.FgCyan).Sprint(dir)))
 
-	return nil
+	return true, nil
 }
 
+// GetReleaseFile returns the name of the release configuration file of a language, as registered in the language registry.
+// Parameters:
+// - projectLanguage: The name or alias of the language.
+// Returns: The name of the release file, empty for an empty language, and an error if the language has no release file.
 func GetReleaseFile(projectLanguage string) (string, error) {
 	if projectLanguage == consts.EMPTY_STRING {
 		return consts.EMPTY_STRING, nil
 	}
 
-	switch strings.ToLower(projectLanguage) {
-	case consts.GO_LANG:
-		return consts.GORELEASER, nil
-	default:
+	file := language.ReleaseFile(projectLanguage)
+	if file == consts.EMPTY_STRING {
 		return consts.EMPTY_STRING, fmt.Errorf("release file for projectLanguage: %s hasn't been implemented yet", projectLanguage)
 	}
+	return file, nil
 }
 
+// GetVersionFile returns the name of the version file of a language, as registered in the language registry.
+// Parameters:
+// - projectLanguage: The name or alias of the language.
+// Returns: The name of the version file, empty for an empty language, and an error if the language has no version file.
 func GetVersionFile(projectLanguage string) (string, error) {
 	if projectLanguage == consts.EMPTY_STRING {
 		return consts.EMPTY_STRING, nil
 	}
 
-	switch strings.ToLower(projectLanguage) {
-	case consts.GO_LANG:
-		return consts.VERSION_GO, nil
-	default:
+	file := language.VersionFile(projectLanguage)
+	if file == consts.EMPTY_STRING {
 		return consts.EMPTY_STRING, fmt.Errorf("version file for projectLanguage: %s hasn't been implemented yet", projectLanguage)
 	}
+	return file, nil
 }
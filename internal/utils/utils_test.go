@@ -193,7 +193,9 @@ func TestGetReleaseFile(t *testing.T) {
 		{"Empty project language", consts.EMPTY_STRING, consts.EMPTY_STRING, false},
 		{"Go language", consts.GO_LANG, consts.GORELEASER, false},
 		{"Go language (uppercase)", "GO", consts.GORELEASER, false},
-		{"Unsupported language", "python", consts.EMPTY_STRING, true},
+		{"Python language", consts.PYTHON, consts.BUMPVERSION, false},
+		{"Python alias", consts.PY_LANG, consts.BUMPVERSION, false},
+		{"Unsupported language", "rust", consts.EMPTY_STRING, true},
 	}
 
 	for _, tt := range tests {
@@ -223,7 +225,9 @@ func TestGetVersionFile(t *testing.T) {
 		{"Empty project language", consts.EMPTY_STRING, consts.EMPTY_STRING, false},
 		{"Go language", consts.GO_LANG, consts.VERSION_GO, false},
 		{"Go language (uppercase)", "GO", consts.VERSION_GO, false},
-		{"Unsupported language", "python", consts.EMPTY_STRING, true},
+		{"Python language", consts.PYTHON, consts.VERSION_PY, false},
+		{"Python alias", "PY", consts.VERSION_PY, false},
+		{"Unsupported language", "rust", consts.EMPTY_STRING, true},
 	}
 
 	for _, tt := range tests {
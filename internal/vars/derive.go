@@ -0,0 +1,91 @@
+package vars
+
+import (
+	"fmt"
+	"strings"
+
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/render"
+)
+
+// IsDerived reports whether the default of a variable is computed from other values, i.e. is text containing a template action.
+// Parameters:
+// - variable: The declared variable.
+// Returns: true if the default is rendered as a template.
+func IsDerived(variable manifest.Variable) bool {
+	def, ok := variable.Default.(string)
+	return ok && strings.Contains(def, "{{")
+}
+
+// Order sorts the declared variables so every variable comes after the declared variables its default refers to,
+// e.g. module = github.com/{{ .owner }}/{{ .project_name }} after owner when owner is declared. Otherwise the declared order is kept.
+// Parameters:
+// - declared: The variables declared by the template manifest.
+// Returns: The ordered variables and an error if a default cannot be parsed or the defaults refer to each other in a cycle.
+func Order(declared []manifest.Variable) ([]manifest.Variable, error) {
+	byName := make(map[string]manifest.Variable, len(declared))
+	for _, variable := range declared {
+		byName[variable.Name] = variable
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(declared))
+	ordered := make([]manifest.Variable, 0, len(declared))
+
+	var visit func(variable manifest.Variable, chain []string) error
+	visit = func(variable manifest.Variable, chain []string) error {
+		switch state[variable.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("variable defaults refer to each other in a cycle: %s", strings.Join(append(chain, variable.Name), " -> "))
+		}
+		state[variable.Name] = visiting
+
+		if IsDerived(variable) {
+			references, err := render.References(variable.Default.(string))
+			if err != nil {
+				return fmt.Errorf("invalid default of %s: %v", variable.Name, err)
+			}
+			for _, name := range references {
+				if dependency, ok := byName[name]; ok {
+					if err := visit(dependency, append(chain, variable.Name)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		state[variable.Name] = visited
+		ordered = append(ordered, variable)
+		return nil
+	}
+
+	for _, variable := range declared {
+		if err := visit(variable, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// derive computes the default of a derived variable by rendering it with the values known so far.
+// Parameters:
+// - variable: The declared variable, whose default is a template.
+// - data: The built-in values and the variables resolved so far.
+// Returns: The default converted to the type of the variable and an error if it could not be rendered or converted.
+func derive(variable manifest.Variable, data render.Data) (any, error) {
+	rendered, err := render.Render(variable.Name, []byte(variable.Default.(string)), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute the default of %s: %v", variable.Name, err)
+	}
+
+	value, err := Convert(string(rendered), variable.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute the default of %s: %v", variable.Name, err)
+	}
+	return value, nil
+}
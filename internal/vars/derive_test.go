@@ -0,0 +1,106 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/types"
+)
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		declared    []manifest.Variable
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name: "Dependencies first",
+			declared: []manifest.Variable{
+				{Name: "module", Default: "github.com/{{ .owner }}/{{ .project_name }}"},
+				{Name: "binary_name", Default: "{{ binaryName .module }}"},
+				{Name: "owner", Default: "acme"},
+			},
+			expected: []string{"owner", "module", "binary_name"},
+		},
+		{
+			name: "Declared order kept",
+			declared: []manifest.Variable{
+				{Name: "b", Default: "{{ .project_name }}"},
+				{Name: "a"},
+			},
+			expected: []string{"b", "a"},
+		},
+		{
+			name: "Cycle",
+			declared: []manifest.Variable{
+				{Name: "a", Default: "{{ .b }}"},
+				{Name: "b", Default: "{{ if .c }}x{{ end }}"},
+				{Name: "c", Default: "{{ .a }}"},
+			},
+			expectedErr: "a -> b -> c -> a",
+		},
+		{
+			name:        "Invalid default",
+			declared:    []manifest.Variable{{Name: "a", Default: "{{ .b "}},
+			expectedErr: "invalid default of a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := Order(tt.declared)
+			if tt.expectedErr != consts.EMPTY_STRING {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, variable := range ordered {
+				names = append(names, variable.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestResolveDerived(t *testing.T) {
+	derived := []manifest.Variable{
+		{Name: "binary_name", Default: "{{ kebabcase .project_name }}"},
+		{Name: "module", Default: "github.com/{{ .owner }}/{{ .binary_name }}"},
+		{Name: "owner"},
+		{Name: "docker", Type: consts.VAR_TYPE_BOOL, Default: `{{ eq .language "go" }}`},
+	}
+	builtins := map[string]any{"project_name": "MyApp", "language": "go"}
+
+	given := map[string]types.Variable{"owner": {Value: "acme", Source: consts.VAR_SOURCE_FLAG}}
+	resolved, err := Resolve(derived, given, builtins, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.Variable{
+		"binary_name": {Value: "my-app", Source: consts.VAR_SOURCE_DEFAULT},
+		"module":      {Value: "github.com/acme/my-app", Source: consts.VAR_SOURCE_DEFAULT},
+		"owner":       {Value: "acme", Source: consts.VAR_SOURCE_FLAG},
+		"docker":      {Value: true, Source: consts.VAR_SOURCE_DEFAULT},
+	}, resolved)
+
+	prompter := &fakePrompter{answers: map[string]string{"owner": "jane"}, defaults: map[string]string{}}
+	resolved, err = Resolve(derived, nil, builtins, prompter)
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/jane/my-app", prompter.defaults["module"])
+	assert.Equal(t, "github.com/jane/my-app", resolved["module"].Value)
+
+	_, err = Resolve(derived, nil, builtins, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "owner")
+	assert.Contains(t, err.Error(), "module")
+
+	_, err = Resolve([]manifest.Variable{{Name: "a", Default: "{{ .unknown }}"}}, nil, builtins, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to compute the default of a")
+}
@@ -0,0 +1,210 @@
+package vars
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/prompt"
+	"github-project-template/internal/render"
+	"github-project-template/internal/types"
+)
+
+// Resolve fills in the variables declared by the template manifest that were not given.
+// With a prompter, the user is asked for each of them, with its default preselected. Without one, the defaults are used,
+// and every declared variable without a value or default is reported at once.
+// The default of a variable is its declared default, or else the built-in value of the same name, e.g. the --project-language value for language.
+// Defaults containing template actions are derived from the other values, e.g. {{ kebabcase .project_name }} or {{ gitConfig "user.name" }},
+// so variables are resolved in the order of their dependencies, see Order.
+// Parameters:
+// - declared: The variables declared by the template manifest.
+// - values: The variables that were given, e.g. by flags, files and environment.
+// - builtins: The built-in template values, used as defaults for declared variables without one and available to derived defaults.
+// - prompter: The Prompter used to ask for missing variables, or nil when not running interactively.
+// Returns: The given variables together with the resolved ones and an error if a prompt fails, a default cannot be derived or variables are missing.
+func Resolve(declared []manifest.Variable, values map[string]types.Variable, builtins map[string]any, prompter prompt.Prompter) (map[string]types.Variable, error) {
+	ordered, err := Order(declared)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := Merge(values)
+	data := make(render.Data, len(builtins)+len(resolved))
+	for key, value := range builtins {
+		data[key] = value
+	}
+	for key, variable := range resolved {
+		data[key] = variable.Value
+	}
+
+	var missing []string
+	unresolved := map[string]bool{}
+	for _, variable := range ordered {
+		if _, ok := resolved[variable.Name]; ok {
+			continue
+		}
+
+		def, hasDefault, err := defaultOf(variable, builtins, data, unresolved)
+		if err != nil {
+			return nil, err
+		}
+
+		if prompter == nil {
+			if hasDefault {
+				resolved[variable.Name] = types.Variable{Value: def, Source: consts.VAR_SOURCE_DEFAULT}
+				data[variable.Name] = def
+				continue
+			}
+			missing = append(missing, describe(variable))
+			unresolved[variable.Name] = true
+			continue
+		}
+
+		value, err := ask(prompter, variable, def)
+		if err != nil {
+			return nil, err
+		}
+		resolved[variable.Name] = types.Variable{Value: value, Source: consts.VAR_SOURCE_PROMPT}
+		data[variable.Name] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing template variables, set them with --var or --vars-file:\n  %s", strings.Join(missing, "\n  "))
+	}
+	return resolved, nil
+}
+
+// defaultOf returns the default of a declared variable: its declared default, derived from the values known so far when it is a template,
+// or else the built-in value of the same name.
+// Parameters:
+// - variable: The declared variable.
+// - builtins: The built-in template values.
+// - data: The built-in values and the variables resolved so far.
+// - unresolved: The variables that are missing, so defaults derived from them cannot be computed.
+// Returns: The default, whether there is one and an error if a derived default could not be computed.
+func defaultOf(variable manifest.Variable, builtins map[string]any, data render.Data, unresolved map[string]bool) (any, bool, error) {
+	if variable.Default == nil {
+		def, ok := builtins[variable.Name]
+		return def, ok, nil
+	}
+	if !IsDerived(variable) {
+		return variable.Default, true, nil
+	}
+
+	references, err := render.References(variable.Default.(string))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, name := range references {
+		if unresolved[name] {
+			return nil, false, nil
+		}
+	}
+
+	def, err := derive(variable, data)
+	if err != nil {
+		return nil, false, err
+	}
+	return def, true, nil
+}
+
+// ask prompts for a single variable, using a select list when it declares choices and a yes/no question for booleans.
+// Parameters:
+// - prompter: The Prompter used to ask.
+// - variable: The declared variable.
+// - def: The default answer, nil when there is none.
+// Returns: The answer converted to the type of the variable and an error if the prompt fails or the answer is not valid for the type.
+func ask(prompter prompt.Prompter, variable manifest.Variable, def any) (any, error) {
+	message := variable.Name
+	if variable.Description != consts.EMPTY_STRING {
+		message = fmt.Sprintf("%s (%s)", variable.Description, variable.Name)
+	}
+
+	defText := consts.EMPTY_STRING
+	if def != nil {
+		defText = fmt.Sprint(def)
+	}
+
+	var (
+		answer string
+		err    error
+	)
+	switch {
+	case len(variable.Choices) > 0:
+		answer, err = prompter.Select(message, variable.Choices, defText)
+	case variable.Type == consts.VAR_TYPE_BOOL:
+		defBool, _ := strconv.ParseBool(defText)
+		var confirmed bool
+		if confirmed, err = prompter.Confirm(message, defBool); err == nil {
+			return confirmed, nil
+		}
+	default:
+		answer, err = prompter.Input(message, defText)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt for %s: %v", variable.Name, err)
+	}
+
+	return Convert(answer, variable.Type)
+}
+
+// describe formats a declared variable for the list of missing variables.
+func describe(variable manifest.Variable) string {
+	if variable.Description == consts.EMPTY_STRING {
+		return variable.Name
+	}
+	return fmt.Sprintf("%s: %s", variable.Name, variable.Description)
+}
+
+// Convert converts the text of a value to the given variable type.
+// Parameters:
+// - value: The value as text, e.g. as typed at a prompt.
+// - variableType: The type of the variable: string (or empty), bool or int.
+// Returns: The converted value and an error if the text is not valid for the type.
+func Convert(value, variableType string) (any, error) {
+	switch variableType {
+	case consts.VAR_TYPE_BOOL:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a bool", value)
+		}
+		return b, nil
+	case consts.VAR_TYPE_INT:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an int", value)
+		}
+		return i, nil
+	default:
+		return value, nil
+	}
+}
+
+// SaveAnswers writes the values of the declared variables to a YAML file, which can be replayed with --vars-file.
+// Parameters:
+// - path: The path of the answers file.
+// - declared: The variables declared by the template manifest.
+// - values: The resolved variables.
+// Returns: An error if the file could not be written.
+func SaveAnswers(path string, declared []manifest.Variable, values map[string]types.Variable) error {
+	answers := make(map[string]any, len(declared))
+	for _, variable := range declared {
+		if value, ok := values[variable.Name]; ok {
+			answers[variable.Name] = value.Value
+		}
+	}
+
+	content, err := yaml.Marshal(answers)
+	if err != nil {
+		return fmt.Errorf("failed to encode answers: %v", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to save answers to %s: %v", path, err)
+	}
+	return nil
+}
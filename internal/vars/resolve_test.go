@@ -0,0 +1,146 @@
+package vars
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/types"
+)
+
+// fakePrompter answers prompts from a map keyed by message and records the defaults it was offered.
+type fakePrompter struct {
+	answers  map[string]string
+	defaults map[string]string
+}
+
+func (f *fakePrompter) Select(message string, choices []string, def string) (string, error) {
+	f.defaults[message] = def
+	return f.answers[message], nil
+}
+
+func (f *fakePrompter) Input(message, def string) (string, error) {
+	f.defaults[message] = def
+	if answer, ok := f.answers[message]; ok {
+		return answer, nil
+	}
+	return def, nil
+}
+
+func (f *fakePrompter) Confirm(message string, def bool) (bool, error) {
+	f.defaults[message] = fmt.Sprint(def)
+	return f.answers[message] == "yes", nil
+}
+
+var declared = []manifest.Variable{
+	{Name: "author", Description: "Name of the author"},
+	{Name: "license", Choices: []string{"mit", "apache"}},
+	{Name: "docker", Type: consts.VAR_TYPE_BOOL, Default: false},
+	{Name: "port", Type: consts.VAR_TYPE_INT, Default: 8080},
+}
+
+func TestResolveNonInteractive(t *testing.T) {
+	given := map[string]types.Variable{"author": {Value: "Jane", Source: consts.VAR_SOURCE_FLAG}}
+
+	resolved, err := Resolve(declared, given, map[string]any{"license": "mit"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.Variable{
+		"author":  {Value: "Jane", Source: consts.VAR_SOURCE_FLAG},
+		"license": {Value: "mit", Source: consts.VAR_SOURCE_DEFAULT},
+		"docker":  {Value: false, Source: consts.VAR_SOURCE_DEFAULT},
+		"port":    {Value: 8080, Source: consts.VAR_SOURCE_DEFAULT},
+	}, resolved)
+
+	_, err = Resolve(declared, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "author: Name of the author")
+	assert.Contains(t, err.Error(), "license")
+	assert.NotContains(t, err.Error(), "port")
+}
+
+func TestResolveInteractive(t *testing.T) {
+	prompter := &fakePrompter{
+		answers: map[string]string{
+			"Name of the author (author)": "Jane",
+			"license":                     "apache",
+			"docker":                      "yes",
+			"port":                        "9090",
+		},
+		defaults: map[string]string{},
+	}
+
+	resolved, err := Resolve(declared, nil, map[string]any{"license": "mit"}, prompter)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.Variable{
+		"author":  {Value: "Jane", Source: consts.VAR_SOURCE_PROMPT},
+		"license": {Value: "apache", Source: consts.VAR_SOURCE_PROMPT},
+		"docker":  {Value: true, Source: consts.VAR_SOURCE_PROMPT},
+		"port":    {Value: 9090, Source: consts.VAR_SOURCE_PROMPT},
+	}, resolved)
+	assert.Equal(t, map[string]string{
+		"Name of the author (author)": consts.EMPTY_STRING,
+		"license":                     "mit",
+		"docker":                      "false",
+		"port":                        "8080",
+	}, prompter.defaults)
+
+	prompter.answers["port"] = "eighty"
+	_, err = Resolve(declared, nil, nil, prompter)
+	assert.Error(t, err)
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value        string
+		variableType string
+		expected     any
+		expectedErr  bool
+	}{
+		{"text", consts.EMPTY_STRING, "text", false},
+		{"text", consts.VAR_TYPE_STRING, "text", false},
+		{"true", consts.VAR_TYPE_BOOL, true, false},
+		{"maybe", consts.VAR_TYPE_BOOL, nil, true},
+		{"42", consts.VAR_TYPE_INT, 42, false},
+		{"4.2", consts.VAR_TYPE_INT, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value+"/"+tt.variableType, func(t *testing.T) {
+			value, err := Convert(tt.value, tt.variableType)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestSaveAnswers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answers.yaml")
+	values := map[string]types.Variable{
+		"author":  {Value: "Jane", Source: consts.VAR_SOURCE_PROMPT},
+		"port":    {Value: 9090, Source: consts.VAR_SOURCE_DEFAULT},
+		"unknown": {Value: "not declared", Source: consts.VAR_SOURCE_ENV},
+	}
+
+	require.NoError(t, SaveAnswers(file, declared, values))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "author: Jane\nport: 9090\n", string(content))
+
+	replayed, err := LoadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.Variable{
+		"author": {Value: "Jane", Source: consts.VAR_SOURCE_FILE},
+		"port":   {Value: 9090, Source: consts.VAR_SOURCE_FILE},
+	}, replayed)
+}
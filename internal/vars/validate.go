@@ -0,0 +1,115 @@
+package vars
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/types"
+)
+
+// Validate checks the variables against the schema declared by the template manifest, so nothing is fetched or written with invalid values.
+// Values given as text, e.g. with --var or environment variables, are converted to the declared type. Every violation is reported at once,
+// together with where the offending value was set.
+// Parameters:
+// - declared: The variables declared by the template manifest.
+// - values: The resolved variables.
+// Returns: The variables with their values converted to the declared types and an error listing every violation.
+func Validate(declared []manifest.Variable, values map[string]types.Variable) (map[string]types.Variable, error) {
+	validated := Merge(values)
+
+	var violations []string
+	for _, variable := range declared {
+		value, ok := validated[variable.Name]
+		if !ok || value.Value == nil || fmt.Sprint(value.Value) == consts.EMPTY_STRING {
+			if variable.Required {
+				violations = append(violations, fmt.Sprintf("%s: is required", variable.Name))
+			}
+			continue
+		}
+
+		converted, err := convertValue(value.Value, variable.Type)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s (%s): %v", variable.Name, value.Source, err))
+			continue
+		}
+		value.Value = converted
+		validated[variable.Name] = value
+
+		if err := check(variable, fmt.Sprint(converted)); err != nil {
+			violations = append(violations, fmt.Sprintf("%s (%s): %v", variable.Name, value.Source, err))
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("invalid template variables:\n  %s", strings.Join(violations, "\n  "))
+	}
+	return validated, nil
+}
+
+// convertValue converts a value to the given variable type. Text is parsed with Convert, and values that already have the type are kept.
+// Parameters:
+// - value: The value of the variable.
+// - variableType: The type of the variable: string (or empty), bool or int.
+// Returns: The converted value and an error if the value is not valid for the type.
+func convertValue(value any, variableType string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return Convert(v, variableType)
+	case bool:
+		if variableType == consts.VAR_TYPE_BOOL {
+			return v, nil
+		}
+	case int:
+		if variableType == consts.VAR_TYPE_INT {
+			return v, nil
+		}
+	}
+
+	if variableType == consts.VAR_TYPE_BOOL || variableType == consts.VAR_TYPE_INT {
+		return nil, fmt.Errorf("%v is not of type %s", value, variableType)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// check validates the text of a value against the choices, pattern and format of the variable.
+// Parameters:
+// - variable: The declared variable.
+// - text: The value as text.
+// Returns: An error describing the first constraint the value violates.
+func check(variable manifest.Variable, text string) error {
+	if len(variable.Choices) > 0 && !contains(variable.Choices, text) {
+		return fmt.Errorf("%q is not one of %s", text, strings.Join(variable.Choices, ", "))
+	}
+
+	if variable.Pattern != consts.EMPTY_STRING {
+		pattern, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", variable.Pattern))
+		if err != nil {
+			return fmt.Errorf("invalid pattern %s: %v", variable.Pattern, err)
+		}
+		if !pattern.MatchString(text) {
+			return fmt.Errorf("%q does not match %s", text, variable.Pattern)
+		}
+	}
+
+	if variable.Format == consts.VAR_FORMAT_SEMVER {
+		if _, err := semver.NewVersion(text); err != nil {
+			return fmt.Errorf("%q is not a semantic version", text)
+		}
+	}
+	return nil
+}
+
+// contains reports whether values contains s.
+func contains(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
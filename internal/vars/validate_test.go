@@ -0,0 +1,83 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/manifest"
+	"github-project-template/internal/types"
+)
+
+var schema = []manifest.Variable{
+	{Name: "module", Required: true, Pattern: `[a-z0-9.-]+(/[A-Za-z0-9_.-]+)+`},
+	{Name: "go_version", Format: consts.VAR_FORMAT_SEMVER},
+	{Name: "license", Choices: []string{"apache", "mit"}},
+	{Name: "docker", Type: consts.VAR_TYPE_BOOL},
+	{Name: "port", Type: consts.VAR_TYPE_INT},
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]types.Variable
+		expected   map[string]types.Variable
+		violations []string
+	}{
+		{
+			name: "Valid",
+			values: map[string]types.Variable{
+				"module":     {Value: "github.com/acme/app", Source: consts.VAR_SOURCE_FLAG},
+				"go_version": {Value: "1.23", Source: consts.VAR_SOURCE_FILE},
+				"license":    {Value: "mit", Source: consts.VAR_SOURCE_PROMPT},
+				"docker":     {Value: "true", Source: consts.VAR_SOURCE_ENV},
+				"port":       {Value: 8080, Source: consts.VAR_SOURCE_FILE},
+			},
+			expected: map[string]types.Variable{
+				"module":     {Value: "github.com/acme/app", Source: consts.VAR_SOURCE_FLAG},
+				"go_version": {Value: "1.23", Source: consts.VAR_SOURCE_FILE},
+				"license":    {Value: "mit", Source: consts.VAR_SOURCE_PROMPT},
+				"docker":     {Value: true, Source: consts.VAR_SOURCE_ENV},
+				"port":       {Value: 8080, Source: consts.VAR_SOURCE_FILE},
+			},
+		},
+		{
+			name: "Every violation",
+			values: map[string]types.Variable{
+				"go_version": {Value: "latest", Source: consts.VAR_SOURCE_FILE},
+				"license":    {Value: "gpl", Source: consts.VAR_SOURCE_ENV},
+				"docker":     {Value: "maybe", Source: consts.VAR_SOURCE_FLAG},
+				"port":       {Value: true, Source: consts.VAR_SOURCE_FILE},
+			},
+			violations: []string{
+				"module: is required",
+				`go_version (file): "latest" is not a semantic version`,
+				`license (env): "gpl" is not one of apache, mit`,
+				`docker (flag): "maybe" is not a bool`,
+				"port (file): true is not of type int",
+			},
+		},
+		{
+			name:       "Pattern",
+			values:     map[string]types.Variable{"module": {Value: "my app", Source: consts.VAR_SOURCE_PROMPT}},
+			violations: []string{`module (prompt): "my app" does not match`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validated, err := Validate(schema, tt.values)
+			if len(tt.violations) > 0 {
+				require.Error(t, err)
+				for _, violation := range tt.violations {
+					assert.Contains(t, err.Error(), violation)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, validated)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package vars
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+// FromMap tags every value of values with the given source.
+// Parameters:
+// - values: The variable values keyed by name, e.g. the vars map of the configuration file.
+// - source: Where the values were set, one of the consts.VAR_SOURCE_* constants.
+// Returns: The variables.
+func FromMap(values map[string]any, source string) map[string]types.Variable {
+	vars := make(map[string]types.Variable, len(values))
+	for key, value := range values {
+		vars[key] = types.Variable{Value: value, Source: source}
+	}
+	return vars
+}
+
+// Parse parses key=value assignments as passed with --var. Everything after the first = is the value, which may be empty.
+// Parameters:
+// - assignments: The key=value assignments.
+// Returns: The variables and an error if an assignment has no = or an empty key.
+func Parse(assignments []string) (map[string]types.Variable, error) {
+	vars := make(map[string]types.Variable, len(assignments))
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == consts.EMPTY_STRING {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value", assignment)
+		}
+		vars[key] = types.Variable{Value: value, Source: consts.VAR_SOURCE_FLAG}
+	}
+	return vars, nil
+}
+
+// LoadFile reads variables from a YAML or JSON file holding a single mapping of names to values.
+// Parameters:
+// - path: The path of the variables file.
+// Returns: The variables and an error if the file could not be read or is not a mapping.
+func LoadFile(path string) (map[string]types.Variable, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vars file %s: %v", path, err)
+	}
+
+	values := map[string]any{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse vars file %s: %v", path, err)
+	}
+	return FromMap(values, consts.VAR_SOURCE_FILE), nil
+}
+
+// FromEnv collects the REPO_STUB_VAR_* environment variables, using the lower cased remainder of the name as the variable name,
+// e.g. REPO_STUB_VAR_PROJECT_NAME sets project_name.
+// Parameters:
+// - environ: The environment in key=value form, as returned by os.Environ.
+// Returns: The variables.
+func FromEnv(environ []string) map[string]types.Variable {
+	vars := make(map[string]types.Variable)
+	for _, entry := range environ {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(key, consts.VAR_ENV_PREFIX) || key == consts.VAR_ENV_PREFIX {
+			continue
+		}
+		vars[strings.ToLower(strings.TrimPrefix(key, consts.VAR_ENV_PREFIX))] = types.Variable{Value: value, Source: consts.VAR_SOURCE_ENV}
+	}
+	return vars
+}
+
+// Merge merges sets of variables, where later sets override earlier ones.
+// Parameters:
+// - sets: The sets of variables ordered from lowest to highest precedence.
+// Returns: The merged variables.
+func Merge(sets ...map[string]types.Variable) map[string]types.Variable {
+	merged := make(map[string]types.Variable)
+	for _, set := range sets {
+		for key, variable := range set {
+			merged[key] = variable
+		}
+	}
+	return merged
+}
@@ -0,0 +1,91 @@
+package vars
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github-project-template/internal/consts"
+	"github-project-template/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		assignments []string
+		expected    map[string]types.Variable
+		expectedErr bool
+	}{
+		{"Single", []string{"author=Jane Doe"}, map[string]types.Variable{"author": {Value: "Jane Doe", Source: consts.VAR_SOURCE_FLAG}}, false},
+		{"Value with equals", []string{"flags=-X main.v=1"}, map[string]types.Variable{"flags": {Value: "-X main.v=1", Source: consts.VAR_SOURCE_FLAG}}, false},
+		{"Empty value", []string{"suffix="}, map[string]types.Variable{"suffix": {Value: consts.EMPTY_STRING, Source: consts.VAR_SOURCE_FLAG}}, false},
+		{"Last one wins", []string{"a=1", "a=2"}, map[string]types.Variable{"a": {Value: "2", Source: consts.VAR_SOURCE_FLAG}}, false},
+		{"Missing equals", []string{"author"}, nil, true},
+		{"Empty key", []string{"=value"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := Parse(tt.assignments)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, vars)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "answers.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("author: Jane Doe\ncli: true\n"), 0644))
+	jsonFile := filepath.Join(dir, "answers.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"module": "github.com/acme/foo"}`), 0644))
+	invalidFile := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("- not\n- a mapping\n"), 0644))
+
+	vars, err := LoadFile(yamlFile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.Variable{
+		"author": {Value: "Jane Doe", Source: consts.VAR_SOURCE_FILE},
+		"cli":    {Value: true, Source: consts.VAR_SOURCE_FILE},
+	}, vars)
+
+	vars, err = LoadFile(jsonFile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]types.Variable{"module": {Value: "github.com/acme/foo", Source: consts.VAR_SOURCE_FILE}}, vars)
+
+	_, err = LoadFile(invalidFile)
+	assert.Error(t, err)
+
+	_, err = LoadFile(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestFromEnv(t *testing.T) {
+	vars := FromEnv([]string{"REPO_STUB_VAR_AUTHOR=Jane", "REPO_STUB_VAR_PROJECT_NAME=foo", "REPO_STUB_VAR_=ignored", "REPO_STUB_API_URL=ignored", "HOME=/root"})
+
+	assert.Equal(t, map[string]types.Variable{
+		"author":       {Value: "Jane", Source: consts.VAR_SOURCE_ENV},
+		"project_name": {Value: "foo", Source: consts.VAR_SOURCE_ENV},
+	}, vars)
+}
+
+func TestMerge(t *testing.T) {
+	merged := Merge(
+		FromMap(map[string]any{"author": "config", "license": "mit"}, consts.VAR_SOURCE_CONFIG),
+		map[string]types.Variable{"author": {Value: "file", Source: consts.VAR_SOURCE_FILE}},
+		map[string]types.Variable{"author": {Value: "env", Source: consts.VAR_SOURCE_ENV}},
+		nil,
+	)
+
+	assert.Equal(t, map[string]types.Variable{
+		"author":  {Value: "env", Source: consts.VAR_SOURCE_ENV},
+		"license": {Value: "mit", Source: consts.VAR_SOURCE_CONFIG},
+	}, merged)
+}